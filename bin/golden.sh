@@ -4,14 +4,25 @@
 # Generate goldens
 #
 
+# left.yml or left (e.g. directory)
+input() {
+    local -r _target="$1"
+    local -r _side="$2"
+    if [[ -f "${_target}/${_side}.yml" ]] ; then
+        echo "${_target}/${_side}.yml"
+    else
+        echo "${_target}/${_side}"
+    fi
+}
+
 run() {
     local -r _target="$1"
     local -r _out="$2"
     echo >&2 "Build golden ${_target} out=${_out}"
     if [[ -s "${_target}/arg.txt" ]] ; then
-        go run ./cmd/objdiff "${_target}/left.yml" "${_target}/right.yml" -o "${_out}" $(cat "${_target}/arg.txt"|tr '\n' " ") 2>/dev/null
+        go run ./cmd/objdiff "$(input "$_target" left)" "$(input "$_target" right)" -o "${_out}" $(cat "${_target}/arg.txt"|tr '\n' " ") 2>/dev/null
     else
-        go run ./cmd/objdiff "${_target}/left.yml" "${_target}/right.yml" -o "${_out}" 2>/dev/null
+        go run ./cmd/objdiff "$(input "$_target" left)" "$(input "$_target" right)" -o "${_out}" 2>/dev/null
     fi
 }
 
@@ -28,6 +39,12 @@ list | while read -r target ; do
     run "$target" yaml > "${target}/out.yml"
     # for output idlist
     run "$target" idlist > "${target}/out.idlist"
+    # optional outputs, update only if exist
+    for out in structural json jsonpatch mergepatch strategicpatch markdown html junit sarif template side-by-side ; do
+        if [[ -f "${target}/out.${out}" ]] ; then
+            run "$target" "$out" > "${target}/out.${out}"
+        fi
+    done
 done
 
 echo >&2 "End golden"
@@ -0,0 +1,42 @@
+package main
+
+import "regexp"
+
+// gitExternalDiff is the arguments of GIT_EXTERNAL_DIFF.
+//
+//	path old-file old-hex old-mode new-file new-hex new-mode [new-path xfrm-msg]
+//
+// The files are /dev/null when the path is added or deleted.
+type gitExternalDiff struct {
+	path    string
+	oldFile string
+	newFile string
+	newPath string
+}
+
+var gitModeRegexp = regexp.MustCompile(`^([0-7]{6}|\.)$`)
+
+// parseGitExternalDiff reads the arguments of GIT_EXTERNAL_DIFF.
+// Returns false if the arguments are not in the calling convention.
+func parseGitExternalDiff(args []string) (*gitExternalDiff, bool) {
+	if len(args) != 7 && len(args) != 9 {
+		return nil, false
+	}
+	if !gitModeRegexp.MatchString(args[3]) || !gitModeRegexp.MatchString(args[6]) {
+		return nil, false
+	}
+	g := &gitExternalDiff{
+		path:    args[0],
+		oldFile: args[1],
+		newFile: args[4],
+		newPath: args[0],
+	}
+	if len(args) == 9 {
+		g.newPath = args[7]
+	}
+	return g, true
+}
+
+func (g *gitExternalDiff) labels() []string {
+	return []string{g.path, g.newPath}
+}
@@ -17,6 +17,30 @@ const usage = `objdiff - k8s object diff by object id
 # Usage
 
   objdiff [flags] LEFT_FILE RIGHT_FILE
+  objdiff [flags] git REV1 REV2 [PATHSPEC...]
+
+LEFT_FILE and RIGHT_FILE are a file, a directory, a glob
+or a comma-separated list of them.
+An existing path is used as it is even if it contains commas or glob characters.
+Directories are walked recursively for *.yaml, *.yml and *.json.
+The documents that are not objects in the walked or globbed files, e.g. kustomization.yaml,
+are skipped with warnings.
+All objects of a side are compared by ID with the objects of the other side.
+
+  objdiff manifests/staging manifests/production
+  objdiff 'left/*.yaml' 'right/*.yaml'
+  objdiff left/app.yml,left/db.yml right/all.yml
+
+objdiff git reads the manifests (*.yaml, *.yml and *.json) at the revisions
+of the git repository in the current directory by git ls-tree and git show.
+All objects of a revision are compared by ID with the objects of the other revision,
+so an object moved to another file is unchanged.
+The documents that are not objects, e.g. CI configs, are skipped with warnings.
+
+  objdiff git main HEAD
+  objdiff -o structural git HEAD~3 HEAD -- manifests/
+
+Flags after -- are read as PATHSPECs.
 
 # Object ID
 
@@ -44,6 +68,97 @@ ID diff.
 
 Unified diff.
 
+--word-diff word or char highlights only the changed words or characters of the changed lines.
+With --color, the changed spans are colored.
+Otherwise, the changed lines are merged like git diff --word-diff:
+
+   image: nginx:1.[-14-]{+15+}.[-3-]{+0+}
+
+## structural
+
+Changed fields keyed by the paths.
+Maps are compared by key and lists of named elements by name,
+so reordering yields no changes.
+
+  ~ spec.replicas: 1 -> 3
+  ~ spec.template.spec.containers[name=app].image: "app:1" -> "app:2"
+  + metadata.labels.tier: web
+  - spec.template.spec.containers[name=sidecar]: {name: sidecar, image: "sidecar:1"}
+
+## markdown
+
+A report for pull request comments.
+The summary table of the counts by kind and namespace,
+followed by the unified diffs in collapsible blocks:
+
+  <details>
+  <summary><code>Object ID</code> will be updated</summary>
+
+  (Unified diff in a diff code block)
+
+  </details>
+
+--maxDiffLines truncates the long diffs with a note.
+
+## html
+
+A self-contained html report without network access.
+
+  objdiff -o html left.yml right.yml > report.html
+
+The object IDs are listed by kind and namespace,
+the objects can be filtered by the diff type,
+and each diff is shown side by side or unified with intra-line highlighting.
+The diffs are from the builtin differ even if --diffCmd is given.
+
+## junit
+
+JUnit XML for CI systems.
+Each object ID is a test case that fails if the object differs,
+with the diff in the failure.
+
+## sarif
+
+SARIF 2.1.0 for CI systems.
+Each changed object is a result located at the file and line of the object on the right side,
+or on the left side if destroyed.
+
+## side-by-side
+
+The left and right objects in two columns like diff -y,
+within the width given by --width.
+The changed lines are marked with | and the lines only on the left or right side with < or >.
+The diffs are from the builtin differ even if --diffCmd is given.
+
+## template
+
+Execute the text/template in the file given by --template, which implies -o template.
+
+  objdiff --template changes.tmpl left.yml right.yml
+
+The data is
+
+  .Left, .Right: "Labels of the sides"
+  .Summary: "Counts of .Add, .Change, .Destroy, .Rename and .Move"
+  .Diffs: Array of
+    .ID, .PrevID, .APIVersion, .Kind, .Namespace, .Name: "Parts of Object ID"
+    .Type: "add, change, destroy, rename, move or unchange"
+    .Diff: "Unified diff, empty if unchanged"
+    .Left, .Right: "Left and right objects, empty if missing"
+    .LeftSource, .RightSource: "Files of the objects"
+    .LeftRev, .RightRev: "Git revisions of the files, empty unless git"
+
+with the functions
+
+  color NAME STRING: "Color STRING by red, green, yellow, cyan or bold if --color"
+  indent N STRING: "Indent the lines of STRING by N spaces"
+  toYaml VALUE, toJson VALUE: "Marshal VALUE"
+
+For example, a csv of the changed objects:
+
+  {{range .Diffs}}{{if ne .Type "unchange"}}{{.Type}},{{.ID}}
+  {{end}}{{end}}
+
 ## yaml
 
 Array of
@@ -51,8 +166,293 @@ Array of
   id: "Object ID"
   diff: "Unified diff"
   left: "Left object (optional)"
+  leftSource: "File of the left object (optional)"
+  leftRev: "Git revision of leftSource (optional)"
+  prevId: "Object ID of the left object if renamed or moved (optional)"
   right: "Right object (optional)"
-  type: "Diff type (add or change or destroy)"
+  rightSource: "File of the right object (optional)"
+  rightRev: "Git revision of rightSource (optional)"
+  type: "Diff type (add or change or destroy or rename or move)"
+
+## json
+
+  {
+    "schemaVersion": 1,
+    "summary": {"add": 0, "change": 1, "destroy": 0, "rename": 0, "move": 0},
+    "entries": [
+      {
+        "id": "Object ID",
+        "prevId": "Object ID of the left object if renamed or moved (optional)",
+        "apiVersion": "apps/v1",
+        "kind": "Deployment",
+        "namespace": "default",
+        "name": "app",
+        "type": "Diff type (add or change or destroy or rename or move)",
+        "diff": "Unified diff",
+        "left": {"Left object": "(optional)"},
+        "leftSource": "File of the left object (optional)",
+        "leftRev": "Git revision of leftSource (optional)",
+        "right": {"Right object": "(optional)"},
+        "rightSource": "File of the right object (optional)",
+        "rightRev": "Git revision of rightSource (optional)"
+      }
+    ]
+  }
+
+schemaVersion changes only when the existing fields change incompatibly.
+
+## jsonpatch
+
+Array of the RFC 6902 JSON Patches that turn the left objects into the right objects.
+
+  [
+    {
+      "id": "Object ID",
+      "apiVersion": "apps/v1",
+      "kind": "Deployment",
+      "namespace": "default",
+      "name": "app",
+      "type": "change",
+      "patch": [
+        {"op": "replace", "path": "/spec/replicas", "value": 3}
+      ]
+    }
+  ]
+
+The fields except patch are the same as json.
+List elements are compared by index.
+An added object is {"op": "add", "path": "", "value": OBJECT}
+and a destroyed object is {"op": "remove", "path": ""}.
+
+## mergepatch
+
+Array of the RFC 7386 JSON merge patches, in the same form as jsonpatch.
+
+  "patch": {"metadata": {"labels": {"tier": null}}, "spec": {"replicas": 3}}
+
+Removed fields are null and lists are replaced as a whole.
+An added object is the whole object and a destroyed object is null.
+
+## strategicpatch
+
+Array of the strategic merge patches, in the same form as jsonpatch.
+Lists with merge keys (see Merge keys) are patched by element,
+and removed elements are $patch: delete directives:
+
+  "patch": {"spec": {"template": {"spec": {"containers": [
+    {"name": "app", "image": "app:2"},
+    {"name": "sidecar", "$patch": "delete"}
+  ]}}}}
+
+The order of the elements is not patched.
+Feed a patch into kubectl patch:
+
+  objdiff -o strategicpatch left.yml right.yml | jq '.[0].patch' | kubectl patch deploy app --type=strategic --patch-file=/dev/stdin
+
+jsonpatch, mergepatch and strategicpatch cannot be combined with --alignList, --stripHashSuffix,
+--normalize=defaults, --normalize=kustomize and --decode,
+because they rewrite the objects into the views that the patches do not apply to.
+
+# Renames
+
+  objdiff -M left.yml right.yml
+  objdiff -M70 left.yml right.yml
+
+pairs an object only in the left and an object only in the right
+if they are the same kind and at least 50% (70%) of the lines are common, like git diff -M.
+The pair is shown as OLD_ID => NEW_ID with the diff,
+a rename if the name changes, or a move if the namespace changes.
+
+# Filter objects
+
+--include and --exclude select the objects by KEY=VALUE[,KEY=VALUE...], matching if all the terms match.
+
+  kind, group, namespace, name: glob, e.g. kind=Deployment, name=app-*
+  id: regex of Object ID, e.g. id=^v1>ConfigMap>
+
+group is the API group of apiVersion, empty if the core group.
+If --include is given, the objects matching none of them are dropped.
+The objects matching any --exclude are dropped.
+The filters apply to every output format and the exit status.
+
+  objdiff --include kind=Deployment,namespace=production left.yml right.yml
+  objdiff --exclude group=apiextensions.k8s.io left.yml right.yml
+
+--selector and --annotation-selector select the objects by the labels and the annotations
+in the syntax of the label selectors of Kubernetes: =, ==, !=, in, notin, KEY and !KEY.
+The pair is compared if the left or right object matches.
+The labels and the annotations are matched as written, before --ignore and --normalize.
+
+  objdiff -l 'app.kubernetes.io/part-of=shop,team in (web,api)' left.yml right.yml
+
+# Normalize
+
+--normalize applies the builtin normalization profiles to the objects before diffing,
+in every output format.
+
+  server: remove the fields populated by the API server,
+          to compare kubectl get -o yaml with the rendered manifests
+  defaults: fill the defaults of the API server in Deployment, StatefulSet, DaemonSet,
+            Job, CronJob, Service, Pod and the pod templates
+  kustomize: same as --stripHashSuffix
+
+The fields removed by the server profile:
+
+  metadata.managedFields
+  metadata.resourceVersion
+  metadata.uid
+  metadata.selfLink
+  metadata.creationTimestamp
+  metadata.generation
+  metadata.annotations["kubectl.kubernetes.io/last-applied-configuration"]
+  metadata.annotations["deployment.kubernetes.io/revision"]
+  spec.template.metadata.creationTimestamp
+  spec.jobTemplate.spec.template.metadata.creationTimestamp
+  status
+
+The emptied maps like metadata.annotations are also removed.
+
+The fields filled by the defaults profile, if missing:
+
+  replicas, revisionHistoryLimit, progressDeadlineSeconds, strategy, updateStrategy, podManagementPolicy
+  completions, parallelism, backoffLimit, completionMode, suspend
+  concurrencyPolicy, successfulJobsHistoryLimit, failedJobsHistoryLimit
+  Service: type, sessionAffinity, internalTrafficPolicy, externalTrafficPolicy, ports[].protocol, ports[].targetPort
+  Pod: restartPolicy, dnsPolicy, schedulerName, terminationGracePeriodSeconds
+  containers: imagePullPolicy, ports[].protocol, terminationMessagePath, terminationMessagePolicy
+
+The defaulted fields are moved to the end of their maps to align them between the sides.
+
+  kubectl get deploy app -o yaml > live.yml
+  objdiff --normalize=server,defaults live.yml manifest.yml
+
+# Canonicalize values
+
+--canonicalize treats the equivalent values as equal:
+
+  resource quantities, e.g. cpu: 1000m and cpu: "1", memory: 1Gi and memory: 1024Mi,
+    in resources.limits, resources.requests, emptyDir.sizeLimit, spec.hard of ResourceQuota,
+    spec.limits[*].{default,defaultRequest,min,max,maxLimitRequestRatio} of LimitRange
+    and spec.capacity of PersistentVolume
+  int-or-string fields, e.g. port: "80" and port: 80,
+    in port, targetPort, containerPort, nodePort, hostPort, maxSurge, maxUnavailable and minAvailable
+  durations, e.g. interval: 60s and interval: 1m0s,
+    in duration, renewBefore, interval, retryInterval and timeout
+
+--canonicalize=canonical rewrites the values into the canonical forms on both sides.
+The quantities keep the formats: the largest binary suffix like 1Gi for the binary suffixes,
+otherwise the largest decimal suffix like 1 or 500m, so 1Gi and 1073741824 are still shown as a change.
+--canonicalize=original keeps the original forms; the equivalent values on the right are shown in the left forms.
+
+# Decode data
+
+--decode rewrites the data of ConfigMaps and Secrets into a readable view before diffing:
+
+  the base64 values of Secret data that decode to texts are moved into stringData
+  the multiline YAML and the JSON of a map or a list in ConfigMap data and Secret stringData
+    are decoded, so that the nested changes are shown under the key
+
+  objdiff --decode left.yml right.yml
+
+The decoded view is not a valid manifest to apply, so the patch outputs are not available.
+The values of the Secrets are redacted unless --redact=false.
+
+# Redact values
+
+The values of Secret data and stringData, and the copy of them in
+metadata.annotations["kubectl.kubernetes.io/last-applied-configuration"], are replaced with the markers like
+
+  <redacted hmac-sha256:0574b240beed>
+
+in all outputs. The marker is the prefix of the HMAC-SHA256 of the value with a random key of the run,
+so the changes are visible without the values, and the values cannot be guessed from the markers.
+--redactKey or OBJDIFF_REDACT_KEY fixes the key to compare the markers across runs;
+keep it secret like the values.
+
+--redactPath redacts the values of the other fields in the form of [KIND:]PATH of --ignore:
+
+  objdiff --redactPath 'ConfigMap:data.password' --redactPath 'spec.template.spec.containers[*].env[name=TOKEN].value' left.yml right.yml
+
+--redact=false shows all values as they are.
+jsonpatch, mergepatch and strategicpatch fail if a patch contains the markers,
+because applying it overwrites the values with the markers; use --redact=false for them.
+
+# Ignore fields
+
+  objdiff --ignore status --ignore 'metadata.annotations["checksum/config"]' left.yml right.yml
+removes status and the annotation from all objects before diffing.
+
+  objdiff --ignore 'Deployment:spec.replicas' left.yml right.yml
+removes spec.replicas only from Deployments.
+
+PATH is a dot-separated path like JSONPath:
+
+  metadata.labels["helm.sh/chart"]   map key
+  spec.containers[0].image           list index
+  spec.containers[*].image           any element
+  spec.containers[name=app].image    list element whose name is app
+  metadata.annotations.*             any key
+
+# Merge keys
+
+List elements like containers, env and ports are matched by their merge keys,
+the keys of the strategic merge patch for the built-in kinds,
+e.g. containers by name, ports by containerPort and volumeMounts by mountPath.
+
+  objdiff --mergeKey 'MyResource:spec.targets=id' -o structural left.yml right.yml
+matches spec.targets of MyResource by id in addition to the built-in keys.
+
+  objdiff --mergeKey 'targets=id' -o structural left.yml right.yml
+matches any list named targets by id.
+
+  objdiff --alignList left.yml right.yml
+sorts the keyed list elements by the keys before diffing
+so that the elements with the same key are aligned in the text diff.
+
+# kustomize hash suffix
+
+  objdiff --stripHashSuffix left.yml right.yml
+
+strips the hash suffixes appended by configMapGenerator and secretGenerator,
+e.g. app-config-7h2kf9t4m8 to app-config,
+so that a ConfigMap or a Secret is paired across content changes.
+The references from envFrom, env.valueFrom, volumes and projected volumes are also stripped.
+
+# kubectl diff
+
+  KUBECTL_EXTERNAL_DIFF=objdiff kubectl diff -f manifest.yml
+  KUBECTL_EXTERNAL_DIFF='objdiff -v -c' kubectl diff -f manifest.yml
+
+kubectl diff invokes objdiff with the LIVE-* and MERGED-* directories
+that contain a file per object named like apps.v1.Deployment.default.app.
+The files are paired by the object IDs from their contents.
+The fields populated by the API server are ignored by --normalize=server unless --keepServerFields.
+
+# git diff
+
+  GIT_EXTERNAL_DIFF=objdiff git diff
+  git -c diff.external='objdiff -c' diff HEAD~ -- manifests/
+
+git diff invokes objdiff with 7 arguments
+
+  PATH OLD_FILE OLD_HEX OLD_MODE NEW_FILE NEW_HEX NEW_MODE
+
+(9 arguments with NEW_PATH and XFRM_MSG for renames).
+The objects are paired by ID and the PATH is the label.
+OLD_FILE or NEW_FILE is /dev/null when the file is added or deleted.
+The exit status is 0 even if the files differ because git stops at non-zero.
+
+To diff only manifests, set the driver by .gitattributes:
+
+  # .gitattributes
+  *.yaml diff=objdiff
+  # .git/config
+  [diff "objdiff"]
+    command = objdiff
+
+git difftool passes 2 files as usual:
+
+  git difftool -y -x objdiff HEAD~
 
 # Exit status
 
@@ -91,7 +491,7 @@ func main() {
 	fs.IntVarP(&c.Context, "context", "C", 3, "diff context")
 	fs.StringVarP(&c.Separator, "separator", "d", ">", "object id separator")
 	fs.IntVarP(&c.Indent, "indent", "n", 2, "yaml indent")
-	fs.StringVarP(&c.Out, "out", "o", "text", "output format: text,yaml,id,idlist")
+	fs.StringVarP(&c.Out, "out", "o", "text", "output format: text,yaml,json,jsonpatch,mergepatch,strategicpatch,markdown,html,junit,sarif,template,side-by-side,id,idlist,structural")
 	fs.BoolVar(&c.Debug, "debug", false, "enable debug log")
 	fs.BoolVarP(&c.Quiet, "quiet", "q", false, "quiet log")
 	fs.BoolVarP(&c.Color, "color", "c", false, "colored diff")
@@ -99,6 +499,28 @@ func main() {
 	fs.BoolVar(&c.AllowDuplicateKey, "allowDuplicateKey", true, "allow the use of keys with the same name in the same map")
 	fs.StringVarP(&c.DiffCommand, "diffCmd", "x", "", "invoke this to get diff instead of builtin differ")
 	fs.BoolVarP(&c.Verbose, "verbose", "v", false, "enable verbose output; annotate diff type and display summary")
+	fs.StringArrayVar(&c.Ignores, "ignore", nil, "ignore fields matching [KIND:]PATH before diffing; repeatable")
+	fs.StringArrayVar(&c.Includes, "include", nil, "compare only the objects matching KEY=VALUE[,KEY=VALUE...]; repeatable")
+	fs.StringArrayVar(&c.Excludes, "exclude", nil, "do not compare the objects matching KEY=VALUE[,KEY=VALUE...]; repeatable")
+	fs.StringVarP(&c.LabelSelector, "selector", "l", "", "compare only the objects matching the label selector, e.g. app=web,tier in (api,db)")
+	fs.StringVar(&c.AnnotationSelector, "annotation-selector", "", "compare only the objects matching the annotation selector in the syntax of --selector")
+	fs.StringSliceVar(&c.Normalize, "normalize", nil, "apply the normalization profiles before diffing: server,defaults,kustomize; repeatable")
+	fs.BoolVar(&c.Redact, "redact", true, "replace the values of Secrets and the fields of --redactPath with the hash markers in all outputs")
+	fs.StringArrayVar(&c.RedactPaths, "redactPath", nil, "redact the fields matching [KIND:]PATH in addition to the Secret values; repeatable")
+	fs.StringVar(&c.RedactKey, "redactKey", "", "key of the redaction markers to make them stable across runs; random if empty; env OBJDIFF_REDACT_KEY")
+	fs.BoolVar(&c.Decode, "decode", false, "decode the base64 data of Secrets into stringData and the YAML and JSON documents in the data of ConfigMaps and Secrets")
+	fs.StringVar(&c.Canonicalize, "canonicalize", "none", "treat the equivalent quantities, ports and durations as equal and show them in: none,canonical,original")
+	fs.StringArrayVar(&c.MergeKeys, "mergeKey", nil, "identify list elements at [KIND:]PATH by KEY, in the form of [KIND:]PATH=KEY; repeatable")
+	fs.BoolVar(&c.AlignList, "alignList", false, "sort list elements by merge key to align them in the text diff")
+	fs.BoolVar(&c.Kubectl, "kubectl", false, "read the directories from kubectl diff; enabled if inputs are LIVE-* and MERGED-* directories")
+	fs.BoolVar(&c.KeepServerFields, "keepServerFields", false, "do not ignore the fields populated by the API server in kubectl mode")
+	fs.IntVarP(&c.FindRenames, "findRenames", "M", 0, "pair the added and destroyed objects of the same kind if their similarity is at least this percentage; 0 disables")
+	fs.Lookup("findRenames").NoOptDefVal = "50"
+	fs.IntVar(&c.MaxDiffLines, "maxDiffLines", 0, "truncate the diff of each object to this number of lines in markdown, junit and sarif; 0 means no limit")
+	fs.StringVar(&c.Template, "template", "", "execute the text/template in this file for -o template; implies -o template")
+	fs.IntVar(&c.Width, "width", 130, "line width of the side-by-side output")
+	fs.StringVar(&c.WordDiff, "word-diff", "none", "highlight the changed words or characters of the changed lines in the builtin differ: none,word,char")
+	fs.BoolVar(&c.StripHashSuffix, "stripHashSuffix", false, "strip kustomize hash suffixes from the names of ConfigMaps and Secrets and the references to them")
 
 	err := fs.Parse(os.Args)
 	if errors.Is(err, pflag.ErrHelp) {
@@ -119,9 +541,24 @@ func main() {
 		slog.Error("invalid context length")
 		os.Exit(exitCodeFailure)
 	}
+	if c.Width < 5 {
+		slog.Error("invalid width", slog.Int("width", c.Width))
+		os.Exit(exitCodeFailure)
+	}
+	if c.FindRenames < 0 || c.FindRenames > 100 {
+		slog.Error("invalid findRenames", slog.Int("findRenames", c.FindRenames))
+		os.Exit(exitCodeFailure)
+	}
 
-	if fs.NArg() != 3 {
-		slog.Error("2 files are required")
+	// not the default of the flag to keep the key out of the help
+	if !fs.Changed("redactKey") {
+		c.RedactKey = os.Getenv("OBJDIFF_REDACT_KEY")
+	}
+	if c.Template != "" && !fs.Changed("out") {
+		c.Out = string(config.OutModeTemplate)
+	}
+	if c.OutMode() == config.OutModeTemplate && c.Template == "" {
+		slog.Error("template is required for out template")
 		os.Exit(exitCodeFailure)
 	}
 	if c.OutMode() == config.OutModeUnknown {
@@ -129,16 +566,43 @@ func main() {
 		os.Exit(exitCodeFailure)
 	}
 
-	if err := c.Run(os.Stdout, fs.Arg(1), fs.Arg(2)); err != nil {
-		if errors.Is(err, config.ErrDiffFound) {
-			if c.DiffSuccess {
-				return
-			}
-			os.Exit(exitCodeDiffFound)
+	left, right := fs.Arg(1), fs.Arg(2)
+	// the external diff of a file named git is not the git subcommand
+	if g, ok := parseGitExternalDiff(fs.Args()[1:]); ok {
+		slog.Debug("git external diff", slog.String("path", g.path))
+		left, right = g.oldFile, g.newFile
+		if len(c.Labels) == 0 {
+			c.Labels = g.labels()
+		}
+		// git stops the diff when the external diff exits with non-zero
+		c.DiffSuccess = true
+	} else if fs.Arg(1) == "git" {
+		if fs.NArg() < 4 {
+			slog.Error("git requires REV1 and REV2")
+			os.Exit(exitCodeFailure)
 		}
-		slog.Error("exit", slog.Any("err", err))
+		exit(c, c.RunGit(os.Stdout, fs.Arg(2), fs.Arg(3), fs.Args()[4:]))
+		return
+	} else if fs.NArg() != 3 {
+		slog.Error("2 files are required")
 		os.Exit(exitCodeFailure)
 	}
+
+	exit(c, c.Run(os.Stdout, left, right))
+}
+
+func exit(c config.Config, err error) {
+	if err == nil {
+		return
+	}
+	if errors.Is(err, config.ErrDiffFound) {
+		if c.DiffSuccess {
+			return
+		}
+		os.Exit(exitCodeDiffFound)
+	}
+	slog.Error("exit", slog.Any("err", err))
+	os.Exit(exitCodeFailure)
 }
 
 func setupLogger(w io.Writer, debug, quiet bool) {
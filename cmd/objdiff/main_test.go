@@ -54,11 +54,19 @@ func TestEndToEnd(t *testing.T) {
 				additionalArgs = strings.Split(strings.TrimSpace(s), " ")
 			}
 
+			// left.yml or left (e.g. directory)
+			input := func(side string) string {
+				if _, err := os.Stat(filepath.Join(dir, side+".yml")); err == nil {
+					return filepath.Join("tests", c.Name(), side+".yml")
+				}
+				return filepath.Join("tests", c.Name(), side)
+			}
+
 			run := func(out string) (string, error) {
 				var buf bytes.Buffer
 				args := []string{
-					filepath.Join("tests", c.Name(), "left.yml"),
-					filepath.Join("tests", c.Name(), "right.yml"),
+					input("left"),
+					input("right"),
 					"-o", out, "--success",
 				}
 				args = append(args, additionalArgs...)
@@ -74,8 +82,9 @@ func TestEndToEnd(t *testing.T) {
 			}
 
 			for _, tc := range []struct {
-				name string
-				file string
+				name     string
+				file     string
+				optional bool
 			}{
 				{
 					name: "id",
@@ -93,9 +102,67 @@ func TestEndToEnd(t *testing.T) {
 					name: "idlist",
 					file: "out.idlist",
 				},
+				{
+					name:     "structural",
+					file:     "out.structural",
+					optional: true,
+				},
+				{
+					name:     "json",
+					file:     "out.json",
+					optional: true,
+				},
+				{
+					name:     "jsonpatch",
+					file:     "out.jsonpatch",
+					optional: true,
+				},
+				{
+					name:     "mergepatch",
+					file:     "out.mergepatch",
+					optional: true,
+				},
+				{
+					name:     "strategicpatch",
+					file:     "out.strategicpatch",
+					optional: true,
+				},
+				{
+					name:     "markdown",
+					file:     "out.markdown",
+					optional: true,
+				},
+				{
+					name:     "html",
+					file:     "out.html",
+					optional: true,
+				},
+				{
+					name:     "junit",
+					file:     "out.junit",
+					optional: true,
+				},
+				{
+					name:     "sarif",
+					file:     "out.sarif",
+					optional: true,
+				},
+				{
+					name:     "template",
+					file:     "out.template",
+					optional: true,
+				},
+				{
+					name:     "side-by-side",
+					file:     "out.side-by-side",
+					optional: true,
+				},
 			} {
 				t.Run(tc.name, func(t *testing.T) {
 					want, err := readAll(tc.file)
+					if tc.optional && os.IsNotExist(err) {
+						t.Skipf("%s not found", tc.file)
+					}
 					if !assert.Nil(t, err) {
 						return
 					}
@@ -108,6 +175,301 @@ func TestEndToEnd(t *testing.T) {
 	}
 }
 
+func TestKubectlExternalDiff(t *testing.T) {
+	e := newExecutor(t)
+	defer e.close()
+
+	const testDir = "../../tests/kubectl"
+	copyDir := func(src, dest string) {
+		if err := os.CopyFS(dest, os.DirFS(src)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dir := t.TempDir()
+	live := filepath.Join(dir, "LIVE-123")
+	merged := filepath.Join(dir, "MERGED-456")
+	copyDir(filepath.Join(testDir, "left"), live)
+	copyDir(filepath.Join(testDir, "right"), merged)
+
+	want, err := os.ReadFile(filepath.Join(testDir, "out.idlist"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	cmd := exec.Command(e.cmd, live, merged, "-o", "idlist")
+	cmd.Stdout = &buf
+	cmd.Stderr = os.Stderr
+	assert.Nil(t, cmd.Run())
+	assert.Equal(t, string(want), buf.String())
+}
+
+func TestGitExternalDiff(t *testing.T) {
+	e := newExecutor(t)
+	defer e.close()
+
+	objdiff := func(arg ...string) (string, error) {
+		var buf bytes.Buffer
+		cmd := exec.Command(e.cmd, arg...)
+		cmd.Dir = "../.."
+		cmd.Stdout = &buf
+		cmd.Stderr = os.Stderr
+		err := cmd.Run()
+		return buf.String(), err
+	}
+
+	const (
+		left  = "tests/diffs/left.yml"
+		right = "tests/diffs/right.yml"
+	)
+	for _, tc := range []struct {
+		name  string
+		args  []string
+		left  string
+		right string
+		label []string
+	}{
+		{
+			name:  "modify",
+			args:  []string{"manifest.yml", left, "1111", "100644", right, "2222", "100644"},
+			left:  left,
+			right: right,
+			label: []string{"manifest.yml", "manifest.yml"},
+		},
+		{
+			name:  "add",
+			args:  []string{"manifest.yml", "/dev/null", ".", ".", right, "2222", "100644"},
+			left:  "/dev/null",
+			right: right,
+			label: []string{"manifest.yml", "manifest.yml"},
+		},
+		{
+			name:  "delete",
+			args:  []string{"manifest.yml", left, "1111", "100644", "/dev/null", ".", "."},
+			left:  left,
+			right: "/dev/null",
+			label: []string{"manifest.yml", "manifest.yml"},
+		},
+		{
+			name:  "file named git",
+			args:  []string{"git", left, "1111", "100644", right, "2222", "100644"},
+			left:  left,
+			right: right,
+			label: []string{"git", "git"},
+		},
+		{
+			name:  "rename",
+			args:  []string{"old.yml", left, "1111", "100644", right, "2222", "100644", "new.yml", "similarity index 90%"},
+			left:  left,
+			right: right,
+			label: []string{"old.yml", "new.yml"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			want, err := objdiff(tc.left, tc.right, "-L", tc.label[0], "-L", tc.label[1], "--success")
+			if !assert.Nil(t, err) {
+				return
+			}
+			got, err := objdiff(tc.args...)
+			assert.Nil(t, err, "exit with 0 even if inputs differ")
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestPatchOfView(t *testing.T) {
+	e := newExecutor(t)
+	defer e.close()
+
+	const input = "../../tests/align-list"
+	for _, out := range []string{"jsonpatch", "mergepatch", "strategicpatch"} {
+		for _, opt := range []string{"--alignList", "--stripHashSuffix", "--normalize=defaults", "--normalize=kustomize", "--decode"} {
+			t.Run(out+" "+opt, func(t *testing.T) {
+				cmd := exec.Command(e.cmd, "-o", out, opt, filepath.Join(input, "left.yml"), filepath.Join(input, "right.yml"))
+				err := cmd.Run()
+				var exitErr *exec.ExitError
+				if assert.ErrorAs(t, err, &exitErr) {
+					assert.Equal(t, 2, exitErr.ExitCode())
+				}
+			})
+		}
+		t.Run(out+" redacted", func(t *testing.T) {
+			const redacted = "../../tests/redact"
+			args := []string{"-o", out, filepath.Join(redacted, "left.yml"), filepath.Join(redacted, "right.yml")}
+			err := exec.Command(e.cmd, args...).Run()
+			var exitErr *exec.ExitError
+			if assert.ErrorAs(t, err, &exitErr) {
+				assert.Equal(t, 2, exitErr.ExitCode(), "the markers overwrite the values")
+			}
+			assert.Nil(t, exec.Command(e.cmd, append(args, "--redact=false", "--success")...).Run())
+		})
+	}
+}
+
+func TestRedactKeyNotInHelp(t *testing.T) {
+	e := newExecutor(t)
+	defer e.close()
+
+	const key = "supersecret"
+	var buf bytes.Buffer
+	cmd := exec.Command(e.cmd, "-h")
+	cmd.Env = append(os.Environ(), "OBJDIFF_REDACT_KEY="+key)
+	cmd.Stdout = &buf
+	cmd.Stderr = &buf
+	assert.Nil(t, cmd.Run())
+	assert.NotContains(t, buf.String(), key)
+}
+
+func TestLiteralFileNames(t *testing.T) {
+	e := newExecutor(t)
+	defer e.close()
+
+	dir := t.TempDir()
+	copyFile := func(src, dest string) string {
+		t.Helper()
+		b, err := os.ReadFile(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dest = filepath.Join(dir, dest)
+		if err := os.WriteFile(dest, b, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return dest
+	}
+	// the existing files are neither globbed nor split by commas
+	left := copyFile("../../tests/diffs/left.yml", "app[prod].yaml")
+	right := copyFile("../../tests/diffs/right.yml", "a,b.yaml")
+	want, err := os.ReadFile("../../tests/diffs/out.idlist")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		args []string
+	}{
+		{
+			name: "files",
+			args: []string{left, right, "-o", "idlist", "--success"},
+		},
+		{
+			name: "git external diff",
+			args: []string{"app[prod].yaml", left, "1111", "100644", right, "2222", "100644", "-o", "idlist"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			cmd := exec.Command(e.cmd, tc.args...)
+			cmd.Stdout = &buf
+			cmd.Stderr = os.Stderr
+			assert.Nil(t, cmd.Run())
+			assert.Equal(t, string(want), buf.String())
+		})
+	}
+}
+
+func TestGitRevisions(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	e := newExecutor(t)
+	defer e.close()
+
+	dir := t.TempDir()
+	git := func(arg ...string) {
+		t.Helper()
+		cmd := exec.Command("git", arg...)
+		cmd.Dir = dir
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	const (
+		cm = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+data:
+  key: value
+`
+		pod = `apiVersion: v1
+kind: Pod
+metadata:
+  name: pod
+spec:
+  containers:
+  - name: app
+    image: app:%s
+`
+	)
+
+	git("init", "-q")
+	git("config", "user.name", "test")
+	git("config", "user.email", "test@example.com")
+	write("manifests/all.yml", cm+"---\n"+strings.ReplaceAll(pod, "%s", "1"))
+	write("README.md", "readme")
+	git("add", "-A")
+	git("commit", "-q", "-m", "first")
+	// move the configmap to another file and update the pod
+	write("manifests/all.yml", strings.ReplaceAll(pod, "%s", "2"))
+	write("manifests/cm.yml", cm)
+	// not an object
+	write(".github/actions/setup/action.yml", "name: setup\nruns:\n  using: composite\n")
+	git("add", "-A")
+	git("commit", "-q", "-m", "second")
+
+	objdiff := func(arg ...string) (string, error) {
+		var buf bytes.Buffer
+		cmd := exec.Command(e.cmd, arg...)
+		cmd.Dir = dir
+		cmd.Stdout = &buf
+		cmd.Stderr = os.Stderr
+		err := cmd.Run()
+		return buf.String(), err
+	}
+
+	got, err := objdiff("git", "HEAD~", "HEAD", "-o", "id")
+	assert.Nil(t, err, "the moved configmap is not an add nor a destroy")
+	assert.Equal(t, "", got)
+
+	got, err = objdiff("-o", "idlist", "git", "HEAD~", "HEAD", "--", "manifests/cm.yml")
+	assert.Nil(t, err)
+	assert.Equal(t, "v1>ConfigMap>>cm\n", got)
+
+	got, err = objdiff("git", "HEAD~", "HEAD", "-o", "structural")
+	assert.NotNil(t, err)
+	assert.Equal(t, `--- HEAD~:manifests/all.yml v1>Pod>>pod
++++ HEAD:manifests/all.yml v1>Pod>>pod
+~ spec.containers[name=app].image: "app:1" -> "app:2"
+`, got)
+
+	got, err = objdiff("git", "HEAD~", "HEAD", "-o", "sarif")
+	assert.NotNil(t, err)
+	assert.Contains(t, got, `"uri": "manifests/all.yml"`, "the revision is not a part of the uri")
+	assert.NotContains(t, got, `"uri": "HEAD`)
+
+	got, err = objdiff("git", "HEAD~", "HEAD", "-o", "json")
+	assert.NotNil(t, err)
+	assert.Contains(t, got, `"rightSource": "manifests/all.yml"`)
+	assert.Contains(t, got, `"rightRev": "HEAD"`)
+
+	_, err = objdiff("git", "HEAD")
+	var exitErr *exec.ExitError
+	if assert.ErrorAs(t, err, &exitErr, "REV2 is required") {
+		assert.Equal(t, 2, exitErr.ExitCode())
+	}
+}
+
 func run(name string, arg ...string) error {
 	cmd := exec.Command(name, arg...)
 	cmd.Dir = "."
@@ -0,0 +1,227 @@
+package config
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/berquerant/k8s-object-diff-go/internal"
+	"github.com/berquerant/k8s-object-diff-go/version"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"`
+}
+
+// printJUnit prints the pairs as the test cases that fail when the objects differ.
+func (p *diffPrinter) printJUnit(ctx context.Context) error {
+	suite := junitTestSuite{
+		Name:  p.left + " " + p.right,
+		Cases: []junitTestCase{},
+	}
+	diffs, err := p.objectDiffs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, d := range diffs {
+		x := d.Pair
+		h := internal.NewReportHeader(d.Pair, d.Type)
+		c := junitTestCase{
+			Name:      x.String(),
+			ClassName: h.Kind,
+			File:      objectLocation(x).Source,
+		}
+		if d.Diff != "" {
+			diff, truncated := truncateLines(d.Diff, p.maxDiffLines)
+			if truncated > 0 {
+				diff += fmt.Sprintf("%d lines truncated.\n", truncated)
+			}
+			c.Failure = &junitFailure{
+				Message: fmt.Sprintf("%s will be %s", x, p.describeDiffType(d.Type)),
+				Type:    d.Type.String(),
+				Text:    diff,
+			}
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, c)
+	}
+	suite.Tests = len(suite.Cases)
+
+	b, err := xml.MarshalIndent(junitTestSuites{
+		Name:     "objdiff",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []junitTestSuite{suite},
+	}, "", strings.Repeat(" ", p.indent))
+	if err != nil {
+		return fmt.Errorf("marshal junit: %w", err)
+	}
+	_, _ = fmt.Fprintf(p.out, "%s%s\n", xml.Header, b)
+	if suite.Failures > 0 {
+		return ErrDiffFound
+	}
+	return nil
+}
+
+// objectLocation returns the right object of the pair, or the left one if destroyed.
+func objectLocation(pair *internal.ObjectPair) *internal.Object {
+	if pair.Right != nil {
+		return pair.Right
+	}
+	return pair.Left
+}
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version"`
+	Rules   []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// printSARIF prints the changed objects as the results located at the objects on the right side.
+func (p *diffPrinter) printSARIF(ctx context.Context) error {
+	results := []sarifResult{}
+	diffs, err := p.collectDiffs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, d := range diffs {
+		diff, truncated := truncateLines(d.Diff, p.maxDiffLines)
+		if truncated > 0 {
+			diff += fmt.Sprintf("%d lines truncated.\n", truncated)
+		}
+		r := sarifResult{
+			RuleID: d.Type.String(),
+			Level:  "warning",
+			Message: sarifMessage{
+				Text: fmt.Sprintf("%s will be %s\n\n%s", d.Pair, p.describeDiffType(d.Type), diff),
+			},
+		}
+		if obj := objectLocation(d.Pair); obj.Source != "" {
+			loc := sarifLocation{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: obj.Source},
+				},
+			}
+			if obj.Line > 0 {
+				loc.PhysicalLocation.Region = &sarifRegion{StartLine: obj.Line}
+			}
+			r.Locations = []sarifLocation{loc}
+		}
+		results = append(results, r)
+	}
+
+	rules := []sarifRule{}
+	for _, t := range []internal.DiffType{
+		internal.DiffTypeAdd,
+		internal.DiffTypeChange,
+		internal.DiffTypeDestroy,
+		internal.DiffTypeRename,
+		internal.DiffTypeMove,
+	} {
+		rules = append(rules, sarifRule{
+			ID:               t.String(),
+			ShortDescription: sarifMessage{Text: "Object will be " + p.describeDiffType(t)},
+		})
+	}
+
+	if err := p.writeJSON(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:    "objdiff",
+						Version: version.Version,
+						Rules:   rules,
+					},
+				},
+				Results: results,
+			},
+		},
+	}); err != nil {
+		return err
+	}
+	if len(results) > 0 {
+		return ErrDiffFound
+	}
+	return nil
+}
@@ -4,24 +4,46 @@ import (
 	"errors"
 	"fmt"
 	"os/exec"
+	"slices"
 	"strings"
 
 	"github.com/berquerant/k8s-object-diff-go/internal"
 )
 
 type Config struct {
-	Context           int
-	Separator         string
-	Indent            int
-	Out               string
-	Debug             bool
-	Quiet             bool
-	Color             bool
-	DiffSuccess       bool
-	AllowDuplicateKey bool
-	DiffCommand       string
-	Verbose           bool
-	Labels            []string
+	Context            int
+	Separator          string
+	Indent             int
+	Out                string
+	Debug              bool
+	Quiet              bool
+	Color              bool
+	DiffSuccess        bool
+	AllowDuplicateKey  bool
+	DiffCommand        string
+	Verbose            bool
+	Labels             []string
+	Ignores            []string
+	MergeKeys          []string
+	AlignList          bool
+	Kubectl            bool
+	KeepServerFields   bool
+	FindRenames        int
+	StripHashSuffix    bool
+	MaxDiffLines       int
+	Template           string
+	Width              int
+	WordDiff           string
+	Includes           []string
+	Excludes           []string
+	LabelSelector      string
+	AnnotationSelector string
+	Normalize          []string
+	Canonicalize       string
+	Decode             bool
+	Redact             bool
+	RedactPaths        []string
+	RedactKey          string
 }
 
 type OutMode string
@@ -32,6 +54,28 @@ const (
 	OutModeYaml    OutMode = "yaml"
 	OutModeID      OutMode = "id"
 	OutModeIDList  OutMode = "idlist"
+	// OutModeStructural renders path-level changes of the objects.
+	OutModeStructural OutMode = "structural"
+	// OutModeJSON renders the report of the diffs.
+	OutModeJSON OutMode = "json"
+	// OutModeJSONPatch renders RFC 6902 JSON Patches of the objects.
+	OutModeJSONPatch OutMode = "jsonpatch"
+	// OutModeMergePatch renders RFC 7386 JSON merge patches of the objects.
+	OutModeMergePatch OutMode = "mergepatch"
+	// OutModeStrategicPatch renders strategic merge patches of the objects.
+	OutModeStrategicPatch OutMode = "strategicpatch"
+	// OutModeMarkdown renders a report for pull request comments.
+	OutModeMarkdown OutMode = "markdown"
+	// OutModeHTML renders a self-contained html report.
+	OutModeHTML OutMode = "html"
+	// OutModeJUnit renders the objects as JUnit XML test cases.
+	OutModeJUnit OutMode = "junit"
+	// OutModeSARIF renders the changed objects as SARIF results.
+	OutModeSARIF OutMode = "sarif"
+	// OutModeTemplate renders the user-defined template.
+	OutModeTemplate OutMode = "template"
+	// OutModeSideBySide renders the objects in two columns.
+	OutModeSideBySide OutMode = "side-by-side"
 )
 
 func (c *Config) OutMode() OutMode {
@@ -44,6 +88,28 @@ func (c *Config) OutMode() OutMode {
 		return OutModeID
 	case string(OutModeIDList):
 		return OutModeIDList
+	case string(OutModeStructural):
+		return OutModeStructural
+	case string(OutModeJSON):
+		return OutModeJSON
+	case string(OutModeJSONPatch):
+		return OutModeJSONPatch
+	case string(OutModeMergePatch):
+		return OutModeMergePatch
+	case string(OutModeStrategicPatch):
+		return OutModeStrategicPatch
+	case string(OutModeMarkdown):
+		return OutModeMarkdown
+	case string(OutModeHTML):
+		return OutModeHTML
+	case string(OutModeJUnit):
+		return OutModeJUnit
+	case string(OutModeSARIF):
+		return OutModeSARIF
+	case string(OutModeTemplate):
+		return OutModeTemplate
+	case string(OutModeSideBySide):
+		return OutModeSideBySide
 	default:
 		return OutModeUnknown
 	}
@@ -67,13 +133,162 @@ func (c *Config) diffCommand() ([]string, error) {
 	return xs, nil
 }
 
+// newMergeKeyRules returns the user-defined rules followed by the default rules.
+func (c *Config) newMergeKeyRules() (internal.MergeKeyRules, error) {
+	rules, err := internal.ParseMergeKeyRules(c.MergeKeys)
+	if err != nil {
+		return nil, err
+	}
+	return append(rules, internal.DefaultMergeKeyRules...), nil
+}
+
+func (c *Config) newNormalizer(mergeKeyRules internal.MergeKeyRules, kubectl bool) (internal.Normalizer, error) {
+	var normalizers internal.Normalizers
+	if c.StripHashSuffix {
+		normalizers = append(normalizers, internal.NewHashSuffixStripper())
+	}
+	if c.Decode {
+		normalizers = append(normalizers, internal.NewDataDecoder())
+	}
+	profiles := c.Normalize
+	if kubectl && !c.KeepServerFields && !slices.Contains(profiles, "server") {
+		profiles = append(slices.Clone(profiles), "server")
+	}
+	for _, x := range profiles {
+		n, err := internal.NewNormalizerProfile(x)
+		if err != nil {
+			return nil, err
+		}
+		normalizers = append(normalizers, n)
+	}
+	canonical, err := internal.ParseCanonicalMode(c.Canonicalize)
+	if err != nil {
+		return nil, err
+	}
+	if canonical == internal.CanonicalValue {
+		normalizers = append(normalizers, internal.NewValueCanonicalizer())
+	}
+	rules, err := internal.ParseIgnoreRules(c.Ignores)
+	if err != nil {
+		return nil, err
+	}
+	normalizers = append(normalizers, rules)
+	if c.AlignList {
+		normalizers = append(normalizers, internal.NewMergeKeyAligner(mergeKeyRules))
+	}
+	if c.Redact {
+		redactRules, err := internal.ParseRedactRules(append(slices.Clone(internal.DefaultRedactRules), c.RedactPaths...))
+		if err != nil {
+			return nil, err
+		}
+		key := []byte(c.RedactKey)
+		if len(key) == 0 {
+			if key, err = internal.NewRedactKey(); err != nil {
+				return nil, err
+			}
+		}
+		normalizers = append(normalizers, internal.NewRedactor(redactRules, key))
+	}
+	return normalizers, nil
+}
+
+// newDiffLabels names the sides of the object diffs.
+// The source files of the objects are preferred unless the labels are specified.
+func (c *Config) newDiffLabels(left, right string) *internal.DiffLabels {
+	labels := &internal.DiffLabels{
+		Left:        left,
+		Right:       right,
+		LeftSource:  true,
+		RightSource: true,
+	}
+	if len(c.Labels) > 0 {
+		labels.Left = c.Labels[0]
+		labels.LeftSource = false
+	}
+	if len(c.Labels) > 1 {
+		labels.Right = c.Labels[1]
+		labels.RightSource = false
+	}
+	return labels
+}
+
+// useColor reports whether to color the output.
+// The documents like markdown are not colored.
+func (c *Config) useColor() bool {
+	switch c.OutMode() {
+	case OutModeMarkdown, OutModeHTML, OutModeJUnit, OutModeSARIF:
+		return false
+	default:
+		return c.Color
+	}
+}
+
+func (c *Config) newObjectDiffer(differ internal.Differ, mergeKeyRules internal.MergeKeyRules, labels *internal.DiffLabels) internal.ObjectDiffer {
+	switch c.OutMode() {
+	case OutModeStructural:
+		return internal.NewStructuralObjectDiffer(labels, c.useColor(), mergeKeyRules)
+	case OutModeJSONPatch:
+		return internal.NewJSONPatchObjectDiffer()
+	case OutModeMergePatch:
+		return internal.NewMergePatchObjectDiffer()
+	case OutModeStrategicPatch:
+		return internal.NewStrategicMergePatchObjectDiffer(mergeKeyRules)
+	}
+	return internal.NewObjectDiffBuilder(
+		differ,
+		labels,
+		c.Context,
+		c.useColor(),
+	)
+}
+
+var ErrWordDiffWithDiffCommand = errors.New("WordDiffWithDiffCommand")
+
+// ErrPatchOfView is returned if a patch is requested with the options that rewrite the objects into views,
+// e.g. --alignList sorts the lists, so that the paths of the patch do not point to the elements of the objects.
+var ErrPatchOfView = errors.New("PatchOfView")
+
+func (c *Config) validatePatch() error {
+	switch c.OutMode() {
+	case OutModeJSONPatch, OutModeMergePatch, OutModeStrategicPatch:
+	default:
+		return nil
+	}
+	var opts []string
+	if c.AlignList {
+		opts = append(opts, "--alignList")
+	}
+	if c.StripHashSuffix {
+		opts = append(opts, "--stripHashSuffix")
+	}
+	for _, x := range []string{"defaults", "kustomize"} {
+		if slices.Contains(c.Normalize, x) {
+			opts = append(opts, "--normalize="+x)
+		}
+	}
+	if c.Decode {
+		opts = append(opts, "--decode")
+	}
+	if len(opts) > 0 {
+		return fmt.Errorf("%w: -o %s with %s", ErrPatchOfView, c.Out, strings.Join(opts, ","))
+	}
+	return nil
+}
+
 func (c *Config) newDiffer() (internal.Differ, error) {
+	wordDiff, err := internal.ParseWordDiffMode(c.WordDiff)
+	if err != nil {
+		return nil, err
+	}
 	cmd, err := c.diffCommand()
 	switch {
 	case err == nil:
+		if wordDiff != internal.WordDiffNone {
+			return nil, ErrWordDiffWithDiffCommand
+		}
 		return internal.NewProcessDiffer(cmd[0], cmd[1:]), nil
 	case errors.Is(err, ErrNoDiffCommand):
-		return internal.NewDMPDiffer(), nil
+		return internal.NewDMPDiffer(wordDiff), nil
 	default:
 		return nil, err
 	}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/berquerant/k8s-object-diff-go/internal"
+)
+
+// dmpView is the diff of a changed pair from the builtin differ.
+type dmpView struct {
+	// Index is the position of the pair in the printer.
+	Index      int
+	Pair       *internal.ObjectPair
+	Type       internal.DiffType
+	LeftLabel  string
+	RightLabel string
+	Views      []*internal.DiffView
+}
+
+// collectDMPViews returns the views of the changed pairs.
+func (p *diffPrinter) collectDMPViews() ([]*dmpView, error) {
+	var views []*dmpView
+	for i, x := range p.pairs {
+		slog.Debug("process pair", slog.String("id", x.String()))
+		if x.IsMissing() {
+			slog.Error("missing object", slog.String("id", x.String()))
+			continue
+		}
+		var leftBody, rightBody string
+		if a := x.Left; a != nil {
+			leftBody = a.Body
+		}
+		if a := x.Right; a != nil {
+			rightBody = a.Body
+		}
+		leftLabel, rightLabel := p.labels.Of(x)
+		result, err := (&internal.DMP{
+			LeftLabel:  leftLabel,
+			RightLabel: rightLabel,
+			Context:    p.diffContext,
+		}).Diff(leftBody, rightBody)
+		if errors.Is(err, internal.ErrDMPNoDiff) {
+			slog.Debug("no diff", slog.String("id", x.String()))
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get diff: id=%s: %w", x, err)
+		}
+		v := &dmpView{
+			Index:      i,
+			Pair:       x,
+			Type:       internal.ChangeTypeOf(x),
+			LeftLabel:  leftLabel,
+			RightLabel: rightLabel,
+			Views:      make([]*internal.DiffView, len(result.Patches)),
+		}
+		for j, patch := range result.Patches {
+			v.Views[j] = internal.NewDiffView(patch)
+		}
+		views = append(views, v)
+	}
+	return views, nil
+}
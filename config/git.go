@@ -0,0 +1,74 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+var _ input = &gitInput{}
+
+// gitInput is the manifests at the revision of the git repository in the current directory.
+type gitInput struct {
+	rev       string
+	pathspecs []string
+}
+
+func newGitInput(rev string, pathspecs []string) *gitInput {
+	return &gitInput{
+		rev:       rev,
+		pathspecs: pathspecs,
+	}
+}
+
+// Files returns the yaml and json files at the revision, relative to the root of the repository.
+// No files is not an error because the files may be added or deleted at the other revision.
+func (x *gitInput) Files(ctx context.Context) ([]string, error) {
+	args := append([]string{"ls-tree", "-r", "-z", "--name-only", "--full-name", x.rev, "--"}, x.pathspecs...)
+	out, err := runGit(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, file := range strings.Split(string(out), "\x00") {
+		if file == "" || !slices.Contains(inputExtensions, strings.ToLower(filepath.Ext(file))) {
+			continue
+		}
+		files = append(files, file)
+	}
+	slog.Debug("git files", slog.String("rev", x.rev), slog.Int("files", len(files)))
+	return files, nil
+}
+
+func (x *gitInput) Open(ctx context.Context, file string) (io.ReadCloser, error) {
+	out, err := runGit(ctx, "show", x.rev+":"+file)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(out)), nil
+}
+
+func (x *gitInput) Source(file string) string { return file }
+func (x *gitInput) Rev() string               { return x.rev }
+
+// Discovered is always true because the files are listed from the tree.
+func (x *gitInput) Discovered(_ string) bool { return true }
+
+func (x *gitInput) String() string { return x.rev }
+
+func runGit(ctx context.Context, arg ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", arg...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", strings.Join(arg, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
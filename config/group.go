@@ -0,0 +1,17 @@
+package config
+
+import "github.com/berquerant/k8s-object-diff-go/internal"
+
+// kindNamespaceKey groups the objects by the kind and the namespace,
+// e.g. the rows of the markdown summary and the sections of the html report.
+type kindNamespaceKey struct {
+	kind      string
+	namespace string
+}
+
+func newKindNamespaceKey(h internal.ReportHeader) kindNamespaceKey {
+	return kindNamespaceKey{
+		kind:      h.Kind,
+		namespace: h.Namespace,
+	}
+}
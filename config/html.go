@@ -0,0 +1,122 @@
+package config
+
+import (
+	"cmp"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"slices"
+
+	"github.com/berquerant/k8s-object-diff-go/internal"
+)
+
+//go:embed templates/report.html
+var htmlReportTemplate string
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"opClass": func(op internal.DMPOp) string {
+		switch op {
+		case internal.DMPOpDelete:
+			return "del"
+		case internal.DMPOpInsert:
+			return "ins"
+		default:
+			return "eq"
+		}
+	},
+}).Parse(htmlReportTemplate))
+
+type htmlReport struct {
+	Left   string
+	Right  string
+	Types  []*htmlTypeCount
+	Groups []*htmlGroup
+}
+
+type htmlTypeCount struct {
+	Type  string
+	Count int
+}
+
+// htmlGroup is the objects of a kind in a namespace.
+type htmlGroup struct {
+	Kind      string
+	Namespace string
+	Entries   []*htmlEntry
+}
+
+type htmlEntry struct {
+	Anchor      string
+	ID          string
+	Type        string
+	Description string
+	LeftLabel   string
+	RightLabel  string
+	Views       []*internal.DiffView
+}
+
+// printHTML prints a self-contained html report.
+// The diffs are always from the builtin differ because the views are built from the patches.
+func (p *diffPrinter) printHTML() error {
+	var (
+		groups = map[kindNamespaceKey]*htmlGroup{}
+		counts = map[internal.DiffType]int{}
+	)
+	views, err := p.collectDMPViews()
+	if err != nil {
+		return err
+	}
+	for _, v := range views {
+		x, diffType := v.Pair, v.Type
+		h := internal.NewReportHeader(x, diffType)
+		key := newKindNamespaceKey(h)
+		g, ok := groups[key]
+		if !ok {
+			g = &htmlGroup{
+				Kind:      h.Kind,
+				Namespace: h.Namespace,
+			}
+			groups[key] = g
+		}
+		g.Entries = append(g.Entries, &htmlEntry{
+			Anchor:      fmt.Sprintf("object-%d", v.Index),
+			ID:          x.String(),
+			Type:        diffType.String(),
+			Description: p.describeDiffType(diffType),
+			LeftLabel:   v.LeftLabel,
+			RightLabel:  v.RightLabel,
+			Views:       v.Views,
+		})
+		counts[diffType]++
+	}
+
+	report := &htmlReport{
+		Left:  p.left,
+		Right: p.right,
+	}
+	for _, t := range []internal.DiffType{
+		internal.DiffTypeAdd,
+		internal.DiffTypeChange,
+		internal.DiffTypeDestroy,
+		internal.DiffTypeRename,
+		internal.DiffTypeMove,
+	} {
+		if n := counts[t]; n > 0 {
+			report.Types = append(report.Types, &htmlTypeCount{Type: t.String(), Count: n})
+		}
+	}
+	for _, g := range groups {
+		report.Groups = append(report.Groups, g)
+	}
+	slices.SortFunc(report.Groups, func(a, b *htmlGroup) int {
+		return cmp.Or(cmp.Compare(a.Kind, b.Kind), cmp.Compare(a.Namespace, b.Namespace))
+	})
+
+	if err := htmlTemplate.Execute(p.out, report); err != nil {
+		return fmt.Errorf("render html: %w", err)
+	}
+	if len(groups) > 0 {
+		return ErrDiffFound
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// inputExtensions are the extensions of the files to be loaded from directories.
+var inputExtensions = []string{".yaml", ".yml", ".json"}
+
+var ErrNoInputFiles = errors.New("NoInputFiles")
+
+// expandInput resolves the input into the files to be loaded.
+//
+// The input is a comma-separated list of files, directories and globs.
+// Directories are walked recursively for yaml and json files.
+// The input that exists as a path is neither split nor globbed, e.g. app[prod].yaml and a,b.yaml.
+func expandInput(input string) ([]string, error) {
+	if _, err := os.Stat(input); err == nil {
+		return expandPath(input)
+	}
+	var files []string
+	for _, x := range strings.Split(input, ",") {
+		if x == "" {
+			continue
+		}
+		xs, err := expandInputElement(x)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, xs...)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoInputFiles, input)
+	}
+	return files, nil
+}
+
+func expandInputElement(x string) ([]string, error) {
+	if _, err := os.Stat(x); err != nil && strings.ContainsAny(x, "*?[") {
+		matches, err := filepath.Glob(x)
+		if err != nil {
+			return nil, fmt.Errorf("glob %s: %w", x, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("%w: glob %s", ErrNoInputFiles, x)
+		}
+		var files []string
+		for _, m := range matches {
+			xs, err := expandPath(m)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, xs...)
+		}
+		return files, nil
+	}
+	return expandPath(x)
+}
+
+// expandPath returns the file, or the yaml and json files under the directory.
+func expandPath(x string) ([]string, error) {
+	info, err := os.Stat(x)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{x}, nil
+	}
+
+	var files []string
+	if err := filepath.WalkDir(x, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !slices.Contains(inputExtensions, strings.ToLower(filepath.Ext(path))) {
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("walk %s: %w", x, err)
+	}
+	slog.Debug("expand directory", slog.String("dir", x), slog.Int("files", len(files)))
+	return files, nil
+}
+
+// input is a side of the diff.
+type input interface {
+	// Files returns the files to be loaded.
+	Files(ctx context.Context) ([]string, error)
+	Open(ctx context.Context, file string) (io.ReadCloser, error)
+	// Source returns the name of the file for the objects.
+	Source(file string) string
+	// Rev returns the git revision of the files, empty if local files.
+	Rev() string
+	// Discovered reports whether the file is found by walking or globbing, not named explicitly.
+	Discovered(file string) bool
+	String() string
+}
+
+var _ input = &fileInput{}
+
+// fileInput is the files from the local filesystem.
+type fileInput struct {
+	input  string
+	expand func(string) ([]string, error)
+}
+
+func newFileInput(input string, expand func(string) ([]string, error)) *fileInput {
+	return &fileInput{
+		input:  input,
+		expand: expand,
+	}
+}
+
+func (x *fileInput) Files(_ context.Context) ([]string, error) { return x.expand(x.input) }
+func (x *fileInput) Open(_ context.Context, file string) (io.ReadCloser, error) {
+	return os.Open(file)
+}
+func (x *fileInput) Source(file string) string { return file }
+func (x *fileInput) Rev() string               { return "" }
+func (x *fileInput) Discovered(file string) bool {
+	return file != x.input && !slices.Contains(strings.Split(x.input, ","), file)
+}
+func (x *fileInput) String() string { return x.input }
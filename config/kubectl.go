@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// kubectl diff invokes KUBECTL_EXTERNAL_DIFF with the directories like LIVE-123 and MERGED-456.
+const (
+	kubectlLiveDirPrefix   = "LIVE-"
+	kubectlMergedDirPrefix = "MERGED-"
+)
+
+// isKubectlMode reports whether the inputs are the directories from kubectl diff.
+func (c *Config) isKubectlMode(left, right string) bool {
+	if c.Kubectl {
+		return true
+	}
+	return strings.HasPrefix(filepath.Base(left), kubectlLiveDirPrefix) &&
+		strings.HasPrefix(filepath.Base(right), kubectlMergedDirPrefix) &&
+		isDir(left) && isDir(right)
+}
+
+func isDir(name string) bool {
+	info, err := os.Stat(name)
+	return err == nil && info.IsDir()
+}
+
+// expandKubectlInput returns all the files in the directory from kubectl diff.
+// The files are named like apps.v1.Deployment.default.app, without extensions.
+func expandKubectlInput(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("kubectl input: %w", err)
+	}
+	var files []string
+	for _, x := range entries {
+		if x.Type().IsRegular() {
+			files = append(files, filepath.Join(dir, x.Name()))
+		}
+	}
+	return files, nil
+}
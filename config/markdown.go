@@ -0,0 +1,140 @@
+package config
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+
+	"github.com/berquerant/k8s-object-diff-go/internal"
+)
+
+// printMarkdown prints the summary table and the diffs in collapsible blocks.
+func (p *diffPrinter) printMarkdown(ctx context.Context) error {
+	diffs, err := p.collectDiffs(ctx)
+	if err != nil {
+		return err
+	}
+	var (
+		counts = map[kindNamespaceKey]map[internal.DiffType]int{}
+		total  = map[internal.DiffType]int{}
+	)
+	for _, d := range diffs {
+		h := internal.NewReportHeader(d.Pair, d.Type)
+		key := newKindNamespaceKey(h)
+		if _, ok := counts[key]; !ok {
+			counts[key] = map[internal.DiffType]int{}
+		}
+		counts[key][d.Type]++
+		total[d.Type]++
+	}
+
+	var b strings.Builder
+	b.WriteString("## objdiff\n\n")
+	if len(diffs) == 0 {
+		b.WriteString("No changes.\n")
+		_, _ = fmt.Fprint(p.out, b.String())
+		return nil
+	}
+
+	types := []internal.DiffType{
+		internal.DiffTypeAdd,
+		internal.DiffTypeChange,
+		internal.DiffTypeDestroy,
+	}
+	for _, t := range []internal.DiffType{internal.DiffTypeRename, internal.DiffTypeMove} {
+		if total[t] > 0 {
+			types = append(types, t)
+		}
+	}
+	writeRow := func(cells ...string) {
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	row := func(head []string, count map[internal.DiffType]int) []string {
+		for _, t := range types {
+			head = append(head, fmt.Sprint(count[t]))
+		}
+		return head
+	}
+	header := []string{"Kind", "Namespace"}
+	for _, t := range types {
+		header = append(header, t.String())
+	}
+	writeRow(header...)
+	separator := make([]string, len(header))
+	for i := range separator {
+		separator[i] = "---"
+		if i >= 2 {
+			separator[i] = "---:"
+		}
+	}
+	writeRow(separator...)
+	keys := make([]kindNamespaceKey, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b kindNamespaceKey) int {
+		return cmp.Or(cmp.Compare(a.kind, b.kind), cmp.Compare(a.namespace, b.namespace))
+	})
+	for _, k := range keys {
+		writeRow(row([]string{markdownEscape(k.kind), markdownEscape(k.namespace)}, counts[k])...)
+	}
+	writeRow(row([]string{"**Total**", ""}, total)...)
+
+	for _, d := range diffs {
+		b.WriteString("\n<details>\n")
+		fmt.Fprintf(&b, "<summary><code>%s</code> will be %s</summary>\n\n",
+			html.EscapeString(d.Pair.String()),
+			p.describeDiffType(d.Type),
+		)
+		diff, truncated := truncateLines(d.Diff, p.maxDiffLines)
+		fence := markdownFence(diff)
+		b.WriteString(fence + "diff\n")
+		b.WriteString(diff)
+		if !strings.HasSuffix(diff, "\n") {
+			b.WriteString("\n")
+		}
+		b.WriteString(fence + "\n")
+		if truncated > 0 {
+			fmt.Fprintf(&b, "\n_%d lines truncated._\n", truncated)
+		}
+		b.WriteString("\n</details>\n")
+	}
+
+	_, _ = fmt.Fprint(p.out, b.String())
+	return ErrDiffFound
+}
+
+// truncateLines returns the first n lines of s and the number of the dropped lines.
+// n <= 0 means no limit.
+func truncateLines(s string, n int) (string, int) {
+	if n <= 0 {
+		return s, 0
+	}
+	lines := strings.SplitAfter(strings.TrimSuffix(s, "\n"), "\n")
+	if len(lines) <= n {
+		return s, 0
+	}
+	return strings.Join(lines[:n], ""), len(lines) - n
+}
+
+// markdownFence returns the code fence longer than any backtick run in s.
+func markdownFence(s string) string {
+	var longest, run int
+	for _, c := range s {
+		if c == '`' {
+			run++
+			longest = max(longest, run)
+			continue
+		}
+		run = 0
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+// markdownEscape escapes the characters that break table cells.
+func markdownEscape(s string) string {
+	return strings.NewReplacer("|", `\|`, "<", "&lt;", ">", "&gt;").Replace(s)
+}
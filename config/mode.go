@@ -2,10 +2,12 @@ package config
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"strings"
+	"text/template"
 
 	"github.com/berquerant/k8s-object-diff-go/internal"
 )
@@ -16,12 +18,17 @@ type diffPrinter struct {
 	differ       internal.Differ
 	objectDiffer internal.ObjectDiffer
 	marshaler    internal.Marshaler
+	indent       int
 	diffContext  int
 	color        bool
 	left         string
 	right        string
 	out          io.Writer
 	verbose      bool
+	maxDiffLines int
+	labels       *internal.DiffLabels
+	template     *template.Template
+	width        int
 }
 
 func (p *diffPrinter) print(ctx context.Context) error {
@@ -32,7 +39,23 @@ func (p *diffPrinter) print(ctx context.Context) error {
 		return p.printObjectIDList()
 	case OutModeYaml:
 		return p.printYamlDiff(ctx)
-	default:
+	case OutModeJSON:
+		return p.printJSONDiff(ctx)
+	case OutModeJSONPatch, OutModeMergePatch, OutModeStrategicPatch:
+		return p.printPatch(ctx)
+	case OutModeMarkdown:
+		return p.printMarkdown(ctx)
+	case OutModeHTML:
+		return p.printHTML()
+	case OutModeJUnit:
+		return p.printJUnit(ctx)
+	case OutModeSARIF:
+		return p.printSARIF(ctx)
+	case OutModeTemplate:
+		return p.printTemplate(ctx)
+	case OutModeSideBySide:
+		return p.printSideBySide()
+	default: // OutModeText, OutModeStructural
 		return p.printTextDiff(ctx)
 	}
 }
@@ -47,6 +70,10 @@ func (diffPrinter) describeDiffType(diffType internal.DiffType) string {
 		return "updated"
 	case internal.DiffTypeDestroy:
 		return "destroyed"
+	case internal.DiffTypeRename:
+		return "renamed"
+	case internal.DiffTypeMove:
+		return "moved"
 	default:
 		return "unknown"
 	}
@@ -64,23 +91,32 @@ func (p *diffPrinter) diffTypeString(id string, diffType internal.DiffType) stri
 	return fmt.Sprintf("%s will be %s", id, desc)
 }
 
-func (p *diffPrinter) diffTypeSummary(add, change, destroy int) string {
+func (p *diffPrinter) diffTypeSummary(count map[internal.DiffType]int) string {
 	head := "Summary:"
 	if p.color {
 		head = internal.BoldString(head)
 	}
-	return fmt.Sprintf("%s %d to %s, %d to %s, %d to %s.",
-		head,
-		add, internal.DiffTypeAdd,
-		change, internal.DiffTypeChange,
-		destroy, internal.DiffTypeDestroy,
-	)
+	xs := []string{}
+	for _, t := range []internal.DiffType{
+		internal.DiffTypeAdd,
+		internal.DiffTypeChange,
+		internal.DiffTypeDestroy,
+		internal.DiffTypeRename,
+		internal.DiffTypeMove,
+	} {
+		n := count[t]
+		if n == 0 && (t == internal.DiffTypeRename || t == internal.DiffTypeMove) {
+			continue // shown only if detected
+		}
+		xs = append(xs, fmt.Sprintf("%d to %s", n, t))
+	}
+	return fmt.Sprintf("%s %s.", head, strings.Join(xs, ", "))
 }
 
 func (p *diffPrinter) printObjectIDList() error {
 	xs := make([]string, len(p.pairs))
 	for i, x := range p.pairs {
-		xs[i] = x.ID
+		xs[i] = x.String()
 	}
 	_, _ = fmt.Fprintln(p.out, strings.Join(xs, "\n"))
 	return nil
@@ -93,7 +129,7 @@ func (p *diffPrinter) printObjectIDDiff(ctx context.Context) error {
 	)
 	for _, x := range p.pairs {
 		if x.Left != nil {
-			leftIDList = append(leftIDList, x.ID)
+			leftIDList = append(leftIDList, x.LeftID())
 		}
 		if x.Right != nil {
 			rightIDList = append(rightIDList, x.ID)
@@ -137,41 +173,25 @@ func (p *diffPrinter) printObjectIDDiff(ctx context.Context) error {
 
 func (p *diffPrinter) printTextDiff(ctx context.Context) error {
 	var (
-		diffFound            bool
-		add, change, destroy int
+		diffFound bool
+		count     = map[internal.DiffType]int{}
 	)
-	for _, x := range p.pairs {
-		slog.Debug("process pair", slog.String("id", x.ID))
-		if x.IsMissing() {
-			slog.Error("missing object", slog.String("id", x.ID))
-			continue
-		}
-		d, err := p.objectDiffer.ObjectDiff(ctx, x)
-		if err != nil {
-			return err
-		}
-		if d.Diff == "" {
-			slog.Debug("no diff", slog.String("id", x.ID))
-			continue
-		}
+	diffs, err := p.collectDiffs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, d := range diffs {
 		if !diffFound {
 			diffFound = true
 		}
-		switch d.Type {
-		case internal.DiffTypeAdd:
-			add++
-		case internal.DiffTypeChange:
-			change++
-		case internal.DiffTypeDestroy:
-			destroy++
-		}
+		count[d.Type]++
 		if p.verbose {
-			_, _ = fmt.Fprintln(p.out, p.diffTypeString(x.ID, d.Type))
+			_, _ = fmt.Fprintln(p.out, p.diffTypeString(d.Pair.String(), d.Type))
 		}
 		_, _ = fmt.Fprint(p.out, d.Diff)
 	}
 	if p.verbose {
-		_, _ = fmt.Fprintf(p.out, "\n%s\n", p.diffTypeSummary(add, change, destroy))
+		_, _ = fmt.Fprintf(p.out, "\n%s\n", p.diffTypeSummary(count))
 	}
 
 	if diffFound {
@@ -186,20 +206,11 @@ func (p *diffPrinter) printYamlDiff(ctx context.Context) error {
 		result    []any
 	)
 
-	for _, x := range p.pairs {
-		slog.Debug("process pair", slog.String("id", x.ID))
-		if x.IsMissing() {
-			slog.Error("missing object", slog.String("id", x.ID))
-			continue
-		}
-		d, err := p.objectDiffer.ObjectDiff(ctx, x)
-		if err != nil {
-			return err
-		}
-		if d.Diff == "" {
-			slog.Debug("no diff", slog.String("id", x.ID))
-			continue
-		}
+	diffs, err := p.collectDiffs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, d := range diffs {
 		if !diffFound {
 			diffFound = true
 		}
@@ -208,11 +219,22 @@ func (p *diffPrinter) printYamlDiff(ctx context.Context) error {
 			"diff": d.Diff,
 			"type": d.Type.String(),
 		}
+		if d.Pair.PrevID != "" {
+			y["prevId"] = d.Pair.PrevID
+		}
 		if a := d.Pair.Left; a != nil {
 			y["left"] = a.Body
+			y["leftSource"] = a.Source
+			if a.Rev != "" {
+				y["leftRev"] = a.Rev
+			}
 		}
 		if a := d.Pair.Right; a != nil {
 			y["right"] = a.Body
+			y["rightSource"] = a.Source
+			if a.Rev != "" {
+				y["rightRev"] = a.Rev
+			}
 		}
 		result = append(result, y)
 	}
@@ -232,3 +254,92 @@ func (p *diffPrinter) printYamlDiff(ctx context.Context) error {
 	}
 	return nil
 }
+
+func (p *diffPrinter) printJSONDiff(ctx context.Context) error {
+	var entries []*internal.ReportEntry
+	diffs, err := p.collectDiffs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, d := range diffs {
+		entries = append(entries, internal.NewReportEntry(d))
+	}
+
+	if err := p.writeJSON(internal.NewReport(entries)); err != nil {
+		return err
+	}
+	if len(entries) > 0 {
+		return ErrDiffFound
+	}
+	return nil
+}
+
+// printPatch prints the patches from the objectDiffer as a json array.
+// The patches that carry the redaction markers are rejected because applying them overwrites the values.
+func (p *diffPrinter) printPatch(ctx context.Context) error {
+	entries := []*internal.PatchEntry{}
+	diffs, err := p.collectDiffs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, d := range diffs {
+		if internal.ContainsRedacted(d.Diff) {
+			return fmt.Errorf("%w: -o %s with the redacted values of %s, use --redact=false", ErrPatchOfView, p.mode, d.Pair)
+		}
+		entries = append(entries, internal.NewPatchEntry(d))
+	}
+
+	if err := p.writeJSON(entries); err != nil {
+		return err
+	}
+	if len(entries) > 0 {
+		return ErrDiffFound
+	}
+	return nil
+}
+
+// objectDiffs returns the diffs of the pairs including the unchanged ones.
+// The missing pairs are skipped.
+func (p *diffPrinter) objectDiffs(ctx context.Context) ([]*internal.ObjectDiff, error) {
+	var diffs []*internal.ObjectDiff
+	for _, x := range p.pairs {
+		slog.Debug("process pair", slog.String("id", x.String()))
+		if x.IsMissing() {
+			slog.Error("missing object", slog.String("id", x.String()))
+			continue
+		}
+		d, err := p.objectDiffer.ObjectDiff(ctx, x)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, d)
+	}
+	return diffs, nil
+}
+
+// collectDiffs returns the diffs of the changed pairs.
+func (p *diffPrinter) collectDiffs(ctx context.Context) ([]*internal.ObjectDiff, error) {
+	diffs, err := p.objectDiffs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var changed []*internal.ObjectDiff
+	for _, d := range diffs {
+		if d.Diff == "" {
+			slog.Debug("no diff", slog.String("id", d.Pair.String()))
+			continue
+		}
+		changed = append(changed, d)
+	}
+	return changed, nil
+}
+
+func (p *diffPrinter) writeJSON(v any) error {
+	enc := json.NewEncoder(p.out)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", strings.Repeat(" ", p.indent))
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("marshal json: %w", err)
+	}
+	return nil
+}
@@ -6,9 +6,9 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
-	"os"
 	"os/signal"
 	"syscall"
+	"text/template"
 
 	"github.com/berquerant/k8s-object-diff-go/internal"
 )
@@ -21,84 +21,177 @@ func (c *Config) Run(w io.Writer, left, right string) error {
 		syscall.SIGINT, syscall.SIGTERM,
 	)
 	defer stop()
-	return c.runObjDiff(ctx, w, left, right)
+	kubectl := c.isKubectlMode(left, right)
+	slog.Debug("mode", slog.Bool("kubectl", kubectl))
+	expand := expandInput
+	if kubectl {
+		expand = expandKubectlInput
+	}
+	return c.runObjDiff(ctx, w, newFileInput(left, expand), newFileInput(right, expand), kubectl)
+}
+
+// RunGit compares the manifests at the revisions of the git repository in the current directory.
+func (c *Config) RunGit(w io.Writer, leftRev, rightRev string, pathspecs []string) error {
+	ctx, stop := signal.NotifyContext(
+		context.Background(),
+		syscall.SIGINT, syscall.SIGTERM,
+	)
+	defer stop()
+	return c.runObjDiff(ctx, w, newGitInput(leftRev, pathspecs), newGitInput(rightRev, pathspecs), false)
 }
 
-func (c *Config) runObjDiff(ctx context.Context, w io.Writer, left, right string) error {
+func (c *Config) runObjDiff(ctx context.Context, w io.Writer, leftInput, rightInput input, kubectl bool) error {
+	left, right := leftInput.String(), rightInput.String()
+	if err := c.validatePatch(); err != nil {
+		return err
+	}
+	var tmpl *template.Template
+	if c.OutMode() == OutModeTemplate {
+		x, err := c.newTemplate(ctx)
+		if err != nil {
+			return fmt.Errorf("template: %w", err)
+		}
+		tmpl = x
+	}
 	marshaler := internal.NewYamlMarshaler(c.Indent, true)
-	leftMap, err := loadObjects(ctx, marshaler, left, c.Separator, c.AllowDuplicateKey)
+	mergeKeyRules, err := c.newMergeKeyRules()
+	if err != nil {
+		return fmt.Errorf("merge key: %w", err)
+	}
+	normalizer, err := c.newNormalizer(mergeKeyRules, kubectl)
+	if err != nil {
+		return fmt.Errorf("normalizer: %w", err)
+	}
+	leftMap, err := loadObjects(ctx, marshaler, normalizer, leftInput, c.Separator, c.AllowDuplicateKey)
 	if err != nil {
 		return fmt.Errorf("left file: %s: %w", left, err)
 	}
-	rightMap, err := loadObjects(ctx, marshaler, right, c.Separator, c.AllowDuplicateKey)
+	rightMap, err := loadObjects(ctx, marshaler, normalizer, rightInput, c.Separator, c.AllowDuplicateKey)
 	if err != nil {
 		return fmt.Errorf("right file: %s: %w", right, err)
 	}
 
-	pairMap := internal.NewObjectPairMap(leftMap, rightMap)
-	pairs := pairMap.ObjectPairs()
+	var pairer internal.ObjectPairer = internal.NewObjectPairMap(leftMap, rightMap)
+	if len(c.Includes) > 0 || len(c.Excludes) > 0 {
+		includes, err := internal.ParseObjectSelectors(c.Includes)
+		if err != nil {
+			return fmt.Errorf("include: %w", err)
+		}
+		excludes, err := internal.ParseObjectSelectors(c.Excludes)
+		if err != nil {
+			return fmt.Errorf("exclude: %w", err)
+		}
+		pairer = internal.NewFilterPairer(pairer, includes, excludes)
+	}
+	if c.LabelSelector != "" || c.AnnotationSelector != "" {
+		labels, err := internal.ParseLabelSelector(c.LabelSelector)
+		if err != nil {
+			return fmt.Errorf("selector: %w", err)
+		}
+		annotations, err := internal.ParseAnnotationSelector(c.AnnotationSelector)
+		if err != nil {
+			return fmt.Errorf("annotation selector: %w", err)
+		}
+		pairer = internal.NewLabelSelectorPairer(pairer, labels, annotations)
+	}
+	if c.FindRenames > 0 {
+		pairer = internal.NewRenamePairer(pairer, float64(c.FindRenames)/100)
+	}
+	pairs := pairer.ObjectPairs()
 	slog.Debug("found pairs", slog.Int("len", len(pairs)))
+	if c.Canonicalize == internal.CanonicalOriginal.String() {
+		if err := internal.AlignEquivalentPairs(ctx, pairs, marshaler); err != nil {
+			return fmt.Errorf("canonicalize: %w", err)
+		}
+	}
 
 	differ, err := c.newDiffer()
 	if err != nil {
 		return fmt.Errorf("differ: %w", err)
 	}
 
-	switch {
-	case len(c.Labels) == 1:
-		left = c.Labels[0]
-	case len(c.Labels) > 1:
-		left, right = c.Labels[0], c.Labels[1]
-	}
+	labels := c.newDiffLabels(left, right)
+	left, right = labels.Left, labels.Right
 
 	printer := &diffPrinter{
-		mode:   c.OutMode(),
-		pairs:  pairs,
-		differ: differ,
-		objectDiffer: internal.NewObjectDiffBuilder(
-			differ,
-			left, right,
-			c.Context,
-			c.Color,
-		),
-		marshaler:   internal.NewYamlMarshaler(c.Indent, false),
-		color:       c.Color,
-		diffContext: c.Context,
-		left:        left,
-		right:       right,
-		out:         w,
-		verbose:     c.Verbose,
+		mode:         c.OutMode(),
+		pairs:        pairs,
+		differ:       differ,
+		objectDiffer: c.newObjectDiffer(differ, mergeKeyRules, labels),
+		marshaler:    internal.NewYamlMarshaler(c.Indent, false),
+		indent:       c.Indent,
+		color:        c.useColor(),
+		diffContext:  c.Context,
+		left:         left,
+		right:        right,
+		out:          w,
+		verbose:      c.Verbose,
+		maxDiffLines: c.MaxDiffLines,
+		labels:       labels,
+		template:     tmpl,
+		width:        c.Width,
 	}
 
 	return printer.print(ctx)
 }
 
-func loadObjects(ctx context.Context, marshaler internal.Marshaler, file, sep string, allowDuplicateMapKey bool) (*internal.ObjectMap, error) {
-	slog.Debug("loadObjects", slog.String("file", file))
-	f, err := os.Open(file)
+func loadObjects(ctx context.Context, marshaler internal.Marshaler, normalizer internal.Normalizer, in input, sep string, allowDuplicateMapKey bool) (*internal.ObjectMap, error) {
+	files, err := in.Files(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open %s: %w", file, err)
+		return nil, err
+	}
+
+	objectMap := internal.NewObjectMap(sep)
+	for _, file := range files {
+		if err := loadObjectsFromFile(ctx, objectMap, marshaler, normalizer, in, file, allowDuplicateMapKey); err != nil {
+			return nil, err
+		}
+	}
+	return objectMap, nil
+}
+
+func loadObjectsFromFile(ctx context.Context, objectMap *internal.ObjectMap, marshaler internal.Marshaler, normalizer internal.Normalizer, in input, file string, allowDuplicateMapKey bool) error {
+	source, rev := in.Source(file), in.Rev()
+	name := internal.SourceLocation(rev, source)
+	slog.Debug("loadObjects", slog.String("file", name))
+	f, err := in.Open(ctx, file)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", name, err)
 	}
 	defer func() {
 		_ = f.Close()
 	}()
 
-	objects, err := internal.LoadObjects(ctx, f, marshaler, allowDuplicateMapKey)
+	discovered := in.Discovered(file)
+	objects, err := internal.LoadObjectsFunc(ctx, f, marshaler, normalizer, allowDuplicateMapKey, func(i int, err error) error {
+		// the directories and the trees contain the files of other tools, e.g. kustomization.yaml and Chart.yaml
+		if discovered && errors.Is(err, internal.ErrLoadObject) {
+			slog.Warn("skip the document that is not an object",
+				slog.String("file", name),
+				slog.Int("index", i),
+				slog.Any("err", err),
+			)
+			return nil
+		}
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to load objects from %s: %w", file, err)
+		return fmt.Errorf("failed to load objects from %s: %w", name, err)
 	}
-	slog.Debug("loaded objects", slog.String("file", file), slog.Int("len", len(objects)))
+	slog.Debug("loaded objects", slog.String("file", name), slog.Int("len", len(objects)))
 
-	objectMap := internal.NewObjectMap(sep)
+	sep := objectMap.Separator()
 	for _, x := range objects {
-		slog.Debug("add object", slog.String("file", file), slog.String("id", x.Header.IntoID(sep)))
+		x.Source = source
+		x.Rev = rev
+		slog.Debug("add object", slog.String("file", name), slog.String("id", x.Header.IntoID(sep)))
 		if objectMap.Add(x) {
 			slog.Warn("duplicated object",
 				slog.String("id", x.Header.IntoID(sep)),
-				slog.String("file", file),
+				slog.String("file", name),
 			)
 		}
 	}
 
-	return objectMap, nil
+	return nil
 }
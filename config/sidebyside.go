@@ -0,0 +1,112 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/berquerant/k8s-object-diff-go/internal"
+)
+
+// sideBySideGutter is the width of the marker column between the sides.
+const sideBySideGutter = 3
+
+// printSideBySide prints the left and right bodies in two columns like diff -y.
+// The diffs are always from the builtin differ because the rows are built from the patches.
+func (p *diffPrinter) printSideBySide() error {
+	var (
+		diffFound bool
+		count     = map[internal.DiffType]int{}
+		column    = (p.width - sideBySideGutter) / 2
+	)
+	views, err := p.collectDMPViews()
+	if err != nil {
+		return err
+	}
+	for _, v := range views {
+		x, diffType := v.Pair, v.Type
+		diffFound = true
+		count[diffType]++
+
+		var b strings.Builder
+		if p.verbose {
+			b.WriteString(p.diffTypeString(x.String(), diffType) + "\n")
+		}
+		b.WriteString(p.colorize(internal.YellowString, "--- "+v.LeftLabel+" "+x.LeftID()) + "\n")
+		b.WriteString(p.colorize(internal.YellowString, "+++ "+v.RightLabel+" "+x.ID) + "\n")
+		for _, view := range v.Views {
+			b.WriteString(p.colorize(internal.CyanString, view.Header) + "\n")
+			for _, row := range view.Rows {
+				b.WriteString(p.sideBySideRow(row, column) + "\n")
+			}
+		}
+		_, _ = fmt.Fprint(p.out, b.String())
+	}
+	if p.verbose {
+		_, _ = fmt.Fprintf(p.out, "\n%s\n", p.diffTypeSummary(count))
+	}
+
+	if diffFound {
+		return ErrDiffFound
+	}
+	return nil
+}
+
+func identString(s string) string { return s }
+
+func (p *diffPrinter) colorize(f func(string) string, s string) string {
+	if p.color {
+		return f(s)
+	}
+	return s
+}
+
+// sideBySideRow renders the row with the marker:
+// space if unchanged, | if changed, < if only on the left, > if only on the right.
+func (p *diffPrinter) sideBySideRow(row *internal.DiffRow, column int) string {
+	var (
+		marker      = " "
+		markerColor = identString
+	)
+	switch {
+	case row.Left == row.Right:
+	case row.Right == nil:
+		marker, markerColor = "<", internal.RedString
+	case row.Left == nil:
+		marker, markerColor = ">", internal.GreenString
+	default:
+		marker, markerColor = "|", internal.YellowString
+	}
+	left := p.sideBySideCell(row.Left, column, internal.RedString)
+	right := p.sideBySideCell(row.Right, column, internal.GreenString)
+	line := left + " " + p.colorize(markerColor, marker) + " " + right
+	return strings.TrimRight(line, " ")
+}
+
+// sideBySideCell renders the line padded or truncated to the width.
+// The changed segments are colored by highlight.
+func (p *diffPrinter) sideBySideCell(line *internal.DiffLine, width int, highlight func(string) string) string {
+	if line == nil {
+		return strings.Repeat(" ", width)
+	}
+	var (
+		b    strings.Builder
+		rest = width
+	)
+	for _, s := range line.Segments {
+		if rest == 0 {
+			break
+		}
+		text := []rune(s.Text)
+		if len(text) > rest {
+			text = text[:rest]
+		}
+		rest -= len(text)
+		if s.Changed || line.Op != internal.DMPOpEqual && len(line.Segments) == 1 {
+			b.WriteString(p.colorize(highlight, string(text)))
+			continue
+		}
+		b.WriteString(string(text))
+	}
+	b.WriteString(strings.Repeat(" ", rest))
+	return b.String()
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/berquerant/k8s-object-diff-go/internal"
+)
+
+// templateData is the data model of the template output.
+type templateData struct {
+	// Left and Right are the labels of the sides.
+	Left    string
+	Right   string
+	Summary *internal.ReportSummary
+	Diffs   []*templateDiff
+}
+
+// templateDiff is the diff of an object pair.
+// Type is unchange if the objects are the same.
+type templateDiff struct {
+	internal.ReportHeader `yaml:",inline"`
+	// Diff is the output of the differ, empty if unchanged.
+	Diff string `json:"diff"`
+	// Left and Right are the yaml bodies, empty if missing.
+	Left        string `json:"left"`
+	Right       string `json:"right"`
+	LeftSource  string `json:"leftSource"`
+	RightSource string `json:"rightSource"`
+	// LeftRev and RightRev are the git revisions of the sources, empty if local files.
+	LeftRev  string `json:"leftRev"`
+	RightRev string `json:"rightRev"`
+}
+
+func newTemplateDiff(d *internal.ObjectDiff) *templateDiff {
+	x := &templateDiff{
+		ReportHeader: internal.NewReportHeader(d.Pair, d.Type),
+		Diff:         d.Diff,
+	}
+	if v := d.Pair.Left; v != nil {
+		x.Left = v.Body
+		x.LeftSource = v.Source
+		x.LeftRev = v.Rev
+	}
+	if v := d.Pair.Right; v != nil {
+		x.Right = v.Body
+		x.RightSource = v.Source
+		x.RightRev = v.Rev
+	}
+	return x
+}
+
+// newTemplate parses the template file with the helper functions.
+func (c *Config) newTemplate(ctx context.Context) (*template.Template, error) {
+	b, err := os.ReadFile(c.Template)
+	if err != nil {
+		return nil, err
+	}
+	marshaler := internal.NewYamlMarshaler(c.Indent, true)
+	color := c.useColor()
+	funcs := template.FuncMap{
+		"color": func(name, s string) (string, error) {
+			f, ok := templateColors[name]
+			if !ok {
+				return "", fmt.Errorf("unknown color: %s", name)
+			}
+			if !color {
+				return s, nil
+			}
+			return f(s), nil
+		},
+		"indent": func(n int, s string) string {
+			prefix := strings.Repeat(" ", n)
+			lines := strings.SplitAfter(s, "\n")
+			for i, line := range lines {
+				if strings.TrimSpace(line) != "" {
+					lines[i] = prefix + line
+				}
+			}
+			return strings.Join(lines, "")
+		},
+		"toYaml": func(v any) (string, error) {
+			b, err := marshaler.Marshal(ctx, v)
+			if err != nil {
+				return "", err
+			}
+			return strings.TrimSuffix(string(b), "\n"), nil
+		},
+		"toJson": func(v any) (string, error) {
+			var buf bytes.Buffer
+			enc := json.NewEncoder(&buf)
+			enc.SetEscapeHTML(false)
+			if err := enc.Encode(v); err != nil {
+				return "", err
+			}
+			return strings.TrimSuffix(buf.String(), "\n"), nil
+		},
+	}
+	return template.New(filepath.Base(c.Template)).Funcs(funcs).Parse(string(b))
+}
+
+var templateColors = map[string]func(string) string{
+	"red":    internal.RedString,
+	"green":  internal.GreenString,
+	"yellow": internal.YellowString,
+	"cyan":   internal.CyanString,
+	"bold":   internal.BoldString,
+}
+
+// printTemplate executes the user-defined template over all the object pairs.
+func (p *diffPrinter) printTemplate(ctx context.Context) error {
+	data := &templateData{
+		Left:    p.left,
+		Right:   p.right,
+		Summary: &internal.ReportSummary{},
+		Diffs:   []*templateDiff{},
+	}
+	diffs, err := p.objectDiffs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, d := range diffs {
+		data.Summary.Count(d.Type)
+		data.Diffs = append(data.Diffs, newTemplateDiff(d))
+	}
+
+	if err := p.template.Execute(p.out, data); err != nil {
+		return fmt.Errorf("execute template: %w", err)
+	}
+	if *data.Summary != (internal.ReportSummary{}) {
+		return ErrDiffFound
+	}
+	return nil
+}
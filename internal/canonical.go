@@ -0,0 +1,427 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CanonicalMode is how the equivalent values are compared and shown.
+type CanonicalMode int
+
+const (
+	CanonicalNone CanonicalMode = iota
+	// CanonicalValue rewrites the values into the canonical forms.
+	CanonicalValue
+	// CanonicalOriginal keeps the original forms, showing the left form if the both sides are equivalent.
+	CanonicalOriginal
+)
+
+func (m CanonicalMode) String() string {
+	switch m {
+	case CanonicalValue:
+		return "canonical"
+	case CanonicalOriginal:
+		return "original"
+	default:
+		return "none"
+	}
+}
+
+var ErrInvalidCanonicalMode = errors.New("InvalidCanonicalMode")
+
+func ParseCanonicalMode(s string) (CanonicalMode, error) {
+	switch s {
+	case "", "none":
+		return CanonicalNone, nil
+	case "canonical":
+		return CanonicalValue, nil
+	case "original":
+		return CanonicalOriginal, nil
+	default:
+		return CanonicalNone, fmt.Errorf("%s: %w", s, ErrInvalidCanonicalMode)
+	}
+}
+
+var (
+	// limitRangeQuantityKeys are the maps of the quantities in LimitRange spec.limits[*].
+	limitRangeQuantityKeys = map[string]bool{
+		"default":              true,
+		"defaultRequest":       true,
+		"min":                  true,
+		"max":                  true,
+		"maxLimitRequestRatio": true,
+	}
+	// intOrStringKeys are the ports and the int-or-string fields that accept numeric strings.
+	intOrStringKeys = map[string]bool{
+		"port":           true,
+		"targetPort":     true,
+		"containerPort":  true,
+		"nodePort":       true,
+		"hostPort":       true,
+		"maxSurge":       true,
+		"maxUnavailable": true,
+		"minAvailable":   true,
+	}
+	// durationKeys are the common duration fields of the custom resources, e.g. Flux and cert-manager.
+	durationKeys = map[string]bool{
+		"duration":      true,
+		"renewBefore":   true,
+		"interval":      true,
+		"retryInterval": true,
+		"timeout":       true,
+	}
+)
+
+// isQuantityField reports whether the map keys from the root of the object point to a resource quantity:
+// resources.limits, resources.requests, ResourceQuota spec.hard, LimitRange spec.limits[*],
+// PersistentVolume spec.capacity and emptyDir.sizeLimit.
+func isQuantityField(kind string, path []string) bool {
+	n := len(path)
+	switch {
+	case n >= 3 && path[n-3] == "resources" && (path[n-2] == "limits" || path[n-2] == "requests"):
+		return true
+	case n >= 2 && path[n-2] == "emptyDir" && path[n-1] == "sizeLimit":
+		return true
+	}
+	switch kind {
+	case "ResourceQuota":
+		return n == 3 && path[0] == "spec" && path[1] == "hard"
+	case "LimitRange":
+		return n == 4 && path[0] == "spec" && path[1] == "limits" && limitRangeQuantityKeys[path[2]]
+	case "PersistentVolume":
+		return n == 3 && path[0] == "spec" && path[1] == "capacity"
+	default:
+		return false
+	}
+}
+
+// canonicalValue returns the canonical form of the value pointed by the map keys from the root of the object.
+func canonicalValue(kind string, path []string, v any) (any, bool) {
+	key := path[len(path)-1]
+	switch {
+	case isQuantityField(kind, path):
+		s, ok := scalarString(v)
+		if !ok {
+			return nil, false
+		}
+		x, ok := CanonicalQuantity(s)
+		if !ok {
+			return nil, false
+		}
+		return x, true
+	case intOrStringKeys[key]:
+		s, ok := v.(string)
+		if !ok {
+			return v, isInteger(v)
+		}
+		x, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, false
+		}
+		return x, true
+	case durationKeys[key]:
+		s, ok := v.(string)
+		if !ok {
+			return nil, false
+		}
+		x, ok := CanonicalDuration(s)
+		if !ok {
+			return nil, false
+		}
+		return x, true
+	default:
+		return nil, false
+	}
+}
+
+// equivalentValue reports whether the values pointed by the map keys from the root of the object are equivalent.
+// The quantities are compared by the values regardless of the formats, e.g. 1Gi and 1073741824.
+func equivalentValue(kind string, path []string, left, right any) bool {
+	if isQuantityField(kind, path) {
+		l, ok := scalarString(left)
+		if !ok {
+			return false
+		}
+		r, ok := scalarString(right)
+		if !ok {
+			return false
+		}
+		x, _, ok := parseQuantity(l)
+		if !ok {
+			return false
+		}
+		y, _, ok := parseQuantity(r)
+		return ok && x.Cmp(y) == 0
+	}
+	l, ok := canonicalValue(kind, path, left)
+	if !ok {
+		return false
+	}
+	r, ok := canonicalValue(kind, path, right)
+	return ok && l == r
+}
+
+func scalarString(v any) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case uint64, int64, int, float64:
+		return fmt.Sprint(v), true
+	default:
+		return "", false
+	}
+}
+
+func isInteger(v any) bool {
+	switch v.(type) {
+	case uint64, int64, int:
+		return true
+	default:
+		return false
+	}
+}
+
+var (
+	quantityRegexp = regexp.MustCompile(`^([+-]?)([0-9]+(?:\.[0-9]*)?|\.[0-9]+)(.*)$`)
+	exponentRegexp = regexp.MustCompile(`^[eE]([+-]?[0-9]+)$`)
+
+	binarySuffixes = []string{"Ki", "Mi", "Gi", "Ti", "Pi", "Ei"}
+	// decimalSuffixes are the suffixes of the powers of 1000 from 10^-9.
+	decimalSuffixes = []string{"n", "u", "m", "", "k", "M", "G", "T", "P", "E"}
+)
+
+// CanonicalQuantity returns the canonical form of the resource quantity, e.g. 1Gi for 1024Mi and 1 for 1000m.
+// Like apimachinery, it keeps the format of the input: the largest binary suffix for a binary suffix,
+// otherwise the largest decimal suffix.
+// It returns false if s is not a quantity or finer than the nano unit.
+func CanonicalQuantity(s string) (string, bool) {
+	value, binary, ok := parseQuantity(s)
+	if !ok {
+		return "", false
+	}
+	if value.Sign() == 0 {
+		return "0", true
+	}
+
+	var result string
+	if x, ok := formatBinaryQuantity(value); ok && binary {
+		result = x
+	} else if x, ok := formatDecimalQuantity(value); ok {
+		result = x
+	} else {
+		return "", false
+	}
+	if value.Sign() < 0 {
+		result = "-" + result
+	}
+	return result, true
+}
+
+// parseQuantity returns the value of the quantity and whether it has a binary suffix.
+func parseQuantity(s string) (*big.Rat, bool, bool) {
+	m := quantityRegexp.FindStringSubmatch(s)
+	if m == nil {
+		return nil, false, false
+	}
+	value, ok := new(big.Rat).SetString(m[2])
+	if !ok {
+		return nil, false, false
+	}
+	scale, ok := quantityScale(m[3])
+	if !ok {
+		return nil, false, false
+	}
+	value.Mul(value, scale)
+	if m[1] == "-" {
+		value.Neg(value)
+	}
+	return value, slices.Contains(binarySuffixes, m[3]), true
+}
+
+// quantityScale returns the multiplier of the suffix.
+func quantityScale(suffix string) (*big.Rat, bool) {
+	for i, x := range binarySuffixes {
+		if x == suffix {
+			return new(big.Rat).SetInt(new(big.Int).Lsh(big.NewInt(1), uint(10*(i+1)))), true
+		}
+	}
+	for i, x := range decimalSuffixes {
+		if x == suffix {
+			return powerOf10(3 * (i - 3)), true
+		}
+	}
+	if m := exponentRegexp.FindStringSubmatch(suffix); m != nil {
+		if e, err := strconv.Atoi(m[1]); err == nil {
+			return powerOf10(e), true
+		}
+	}
+	return nil, false
+}
+
+func powerOf10(e int) *big.Rat {
+	n := int64(e)
+	if n < 0 {
+		n = -n
+	}
+	x := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(n), nil))
+	if e < 0 {
+		return x.Inv(x)
+	}
+	return x
+}
+
+func formatDecimalQuantity(value *big.Rat) (string, bool) {
+	abs := new(big.Rat).Abs(value)
+	for i := len(decimalSuffixes) - 1; i >= 0; i-- {
+		x := new(big.Rat).Quo(abs, powerOf10(3*(i-3)))
+		if x.IsInt() {
+			return x.Num().String() + decimalSuffixes[i], true
+		}
+	}
+	return "", false
+}
+
+func formatBinaryQuantity(value *big.Rat) (string, bool) {
+	abs := new(big.Rat).Abs(value)
+	if !abs.IsInt() {
+		return "", false
+	}
+	for i := len(binarySuffixes) - 1; i >= 0; i-- {
+		p := new(big.Int).Lsh(big.NewInt(1), uint(10*(i+1)))
+		q, r := new(big.Int).QuoRem(abs.Num(), p, new(big.Int))
+		if r.Sign() == 0 {
+			return q.String() + binarySuffixes[i], true
+		}
+	}
+	return "", false
+}
+
+// CanonicalDuration returns the canonical form of the duration, e.g. 1m30s for 90s.
+// It returns false if s is not a duration with units.
+func CanonicalDuration(s string) (string, bool) {
+	if !strings.ContainsAny(s, "hmsµun") {
+		return "", false
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return "", false
+	}
+	return d.String(), true
+}
+
+var _ Normalizer = &ValueCanonicalizer{}
+
+// ValueCanonicalizer rewrites the resource quantities, the int-or-string fields and the durations
+// into the canonical forms, so that the equivalent values compare equal.
+type ValueCanonicalizer struct{}
+
+func NewValueCanonicalizer() *ValueCanonicalizer {
+	return &ValueCanonicalizer{}
+}
+
+func (c *ValueCanonicalizer) Normalize(_ context.Context, obj map[string]any) error {
+	kind, _ := obj["kind"].(string)
+	for k, v := range obj {
+		obj[k] = c.canonicalize(kind, []string{k}, v)
+	}
+	return nil
+}
+
+// canonicalize rewrites the values of the node pointed by the map keys from the root.
+func (c *ValueCanonicalizer) canonicalize(kind string, path []string, v any) any {
+	if xs, ok := v.([]any); ok {
+		for i, x := range xs {
+			xs[i] = c.canonicalize(kind, path, x)
+		}
+		return xs
+	}
+	items, ok := treeItems(v)
+	if !ok {
+		if x, ok := canonicalValue(kind, path, v); ok {
+			return x
+		}
+		return v
+	}
+	for _, x := range items {
+		k := treeKeyString(x.Key)
+		v = treeSet(v, k, c.canonicalize(kind, append(path, k), x.Value))
+	}
+	return v
+}
+
+// AlignEquivalentValues replaces the values of right equivalent to the values of left by the left ones,
+// so that only the different values are shown in their original forms.
+// It returns true if right is updated.
+func AlignEquivalentValues(left, right map[string]any) bool {
+	var updated bool
+	kind, _ := right["kind"].(string)
+	for k, v := range right {
+		if x, ok := left[k]; ok {
+			right[k] = alignEquivalentValue(kind, []string{k}, x, v, &updated)
+		}
+	}
+	return updated
+}
+
+func alignEquivalentValue(kind string, path []string, left, right any, updated *bool) any {
+	if rs, ok := right.([]any); ok {
+		ls, ok := left.([]any)
+		if !ok {
+			return right
+		}
+		for i := range min(len(ls), len(rs)) {
+			rs[i] = alignEquivalentValue(kind, path, ls[i], rs[i], updated)
+		}
+		return rs
+	}
+	items, ok := treeItems(right)
+	if !ok {
+		if _, ok := treeItems(left); ok {
+			return right
+		}
+		if _, ok := left.([]any); ok {
+			return right
+		}
+		if left == right {
+			return right
+		}
+		if !equivalentValue(kind, path, left, right) {
+			return right
+		}
+		*updated = true
+		return left
+	}
+	for _, x := range items {
+		k := treeKeyString(x.Key)
+		if y, ok := treeGet(left, k); ok {
+			right = treeSet(right, k, alignEquivalentValue(kind, append(path, k), y, x.Value, updated))
+		}
+	}
+	return right
+}
+
+// AlignEquivalentPairs applies [AlignEquivalentValues] to the paired objects
+// and marshals the updated right objects again.
+func AlignEquivalentPairs(ctx context.Context, pairs []*ObjectPair, marshaler Marshaler) error {
+	for _, pair := range pairs {
+		if pair.Left == nil || pair.Right == nil || pair.Left.Body == pair.Right.Body {
+			continue
+		}
+		if !AlignEquivalentValues(pair.Left.Value, pair.Right.Value) {
+			continue
+		}
+		b, err := marshaler.Marshal(ctx, pair.Right.Value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", pair.ID, err)
+		}
+		pair.Right.Body = string(b)
+	}
+	return nil
+}
@@ -0,0 +1,265 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/berquerant/k8s-object-diff-go/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalQuantity(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		want  string
+		ok    bool
+	}{
+		{input: "1", want: "1", ok: true},
+		{input: "1000m", want: "1", ok: true},
+		{input: "0.5", want: "500m", ok: true},
+		{input: ".5", want: "500m", ok: true},
+		{input: "1500m", want: "1500m", ok: true},
+		{input: "1Gi", want: "1Gi", ok: true},
+		{input: "1024Mi", want: "1Gi", ok: true},
+		{input: "1.5Gi", want: "1536Mi", ok: true},
+		{input: "128974848", want: "128974848", ok: true},
+		{input: "1024", want: "1024", ok: true},
+		{input: "1.5Ki", want: "1536", ok: true},
+		{input: "1048576Ki", want: "1Gi", ok: true},
+		{input: "1G", want: "1G", ok: true},
+		{input: "1000M", want: "1G", ok: true},
+		{input: "1e3", want: "1k", ok: true},
+		{input: "1E", want: "1E", ok: true},
+		{input: "-2000m", want: "-2", ok: true},
+		{input: "0Mi", want: "0", ok: true},
+		{input: "1Xi"},
+		{input: "0.1n"},
+		{input: "abc"},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			got, ok := internal.CanonicalQuantity(tc.input)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestCanonicalDuration(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		want  string
+		ok    bool
+	}{
+		{input: "60s", want: "1m0s", ok: true},
+		{input: "1m", want: "1m0s", ok: true},
+		{input: "1h30m", want: "1h30m0s", ok: true},
+		{input: "0"},
+		{input: "none"},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			got, ok := internal.CanonicalDuration(tc.input)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestValueCanonicalizer(t *testing.T) {
+	for _, tc := range []struct {
+		title    string
+		manifest string
+		want     string
+	}{
+		{
+			title: "pod",
+			manifest: `apiVersion: v1
+kind: Pod
+metadata:
+  name: app
+spec:
+  containers:
+  - name: app
+    ports:
+    - containerPort: "8080"
+      name: http
+    readinessProbe:
+      httpGet:
+        port: http
+    resources:
+      limits:
+        cpu: 1
+        memory: 1024Mi
+  volumes:
+  - name: cache
+    emptyDir:
+      sizeLimit: 1024Mi
+`,
+			want: `apiVersion: v1
+kind: Pod
+metadata:
+  name: app
+spec:
+  containers:
+  - name: app
+    ports:
+    - containerPort: 8080
+      name: http
+    readinessProbe:
+      httpGet:
+        port: http
+    resources:
+      limits:
+        cpu: "1"
+        memory: 1Gi
+  volumes:
+  - name: cache
+    emptyDir:
+      sizeLimit: 1Gi
+`,
+		},
+		{
+			title: "limitrange",
+			manifest: `apiVersion: v1
+kind: LimitRange
+metadata:
+  name: limits
+spec:
+  limits:
+  - type: Container
+    max:
+      cpu: 2000m
+    default:
+      memory: 1024Mi
+`,
+			want: `apiVersion: v1
+kind: LimitRange
+metadata:
+  name: limits
+spec:
+  limits:
+  - type: Container
+    max:
+      cpu: "2"
+    default:
+      memory: 1Gi
+`,
+		},
+		{
+			title: "resourcequota",
+			manifest: `apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: quota
+spec:
+  hard:
+    requests.cpu: 1000m
+    pods: 10
+`,
+			want: `apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: quota
+spec:
+  hard:
+    requests.cpu: "1"
+    pods: "10"
+`,
+		},
+		{
+			title: "persistentvolume",
+			manifest: `apiVersion: v1
+kind: PersistentVolume
+metadata:
+  name: pv
+spec:
+  capacity:
+    storage: 10240Mi
+`,
+			want: `apiVersion: v1
+kind: PersistentVolume
+metadata:
+  name: pv
+spec:
+  capacity:
+    storage: 10Gi
+`,
+		},
+		{
+			title: "other fields",
+			manifest: `apiVersion: example.com/v1
+kind: Proxy
+metadata:
+  name: proxy
+spec:
+  max:
+    connections: 1000
+  hard:
+    cpu: 1000m
+  capacity:
+    storage: 1024Mi
+`,
+			want: `apiVersion: example.com/v1
+kind: Proxy
+metadata:
+  name: proxy
+spec:
+  max:
+    connections: 1000
+  hard:
+    cpu: 1000m
+  capacity:
+    storage: 1024Mi
+`,
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			assert.Equal(t, tc.want, normalizeManifest(t, internal.NewValueCanonicalizer(), tc.manifest))
+		})
+	}
+}
+
+func TestAlignEquivalentValues(t *testing.T) {
+	left := map[string]any{
+		"spec": map[string]any{
+			"ports": []any{
+				map[string]any{"port": "80"},
+			},
+			"resources": map[string]any{
+				"requests": map[string]any{"cpu": "1000m", "memory": "1Gi"},
+				"limits":   map[string]any{"memory": "1Gi"},
+			},
+		},
+	}
+	right := map[string]any{
+		"spec": map[string]any{
+			"ports": []any{
+				map[string]any{"port": uint64(80)},
+			},
+			"resources": map[string]any{
+				"requests": map[string]any{"cpu": uint64(1), "memory": "2Gi"},
+				"limits":   map[string]any{"memory": "1073741824"},
+			},
+		},
+	}
+	assert.True(t, internal.AlignEquivalentValues(left, right))
+	assert.Equal(t, map[string]any{
+		"spec": map[string]any{
+			"ports": []any{
+				map[string]any{"port": "80"},
+			},
+			"resources": map[string]any{
+				"requests": map[string]any{"cpu": "1000m", "memory": "2Gi"},
+				"limits":   map[string]any{"memory": "1Gi"},
+			},
+		},
+	}, right)
+	assert.False(t, internal.AlignEquivalentValues(left, right))
+}
+
+func TestParseCanonicalMode(t *testing.T) {
+	for _, s := range []string{"", "none", "canonical", "original"} {
+		_, err := internal.ParseCanonicalMode(s)
+		assert.Nil(t, err, s)
+	}
+	_, err := internal.ParseCanonicalMode("unknown")
+	assert.ErrorIs(t, err, internal.ErrInvalidCanonicalMode)
+}
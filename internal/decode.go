@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/goccy/go-yaml"
+)
+
+var _ Normalizer = &DataDecoder{}
+
+// DataDecoder rewrites the data of ConfigMaps and Secrets into a readable view.
+// The base64 values of Secret data are decoded into stringData,
+// and the YAML and JSON documents embedded in the values are decoded into the trees.
+type DataDecoder struct{}
+
+func NewDataDecoder() *DataDecoder {
+	return &DataDecoder{}
+}
+
+func (d *DataDecoder) Normalize(_ context.Context, obj map[string]any) error {
+	switch obj["kind"] {
+	case "ConfigMap":
+		if data, ok := obj["data"]; ok {
+			obj["data"] = decodeEmbeddedValues(data)
+		}
+	case "Secret":
+		if data, ok := obj["data"]; ok {
+			data, stringData := decodeSecretData(data, obj["stringData"])
+			if items, ok := treeItems(data); ok && len(items) == 0 {
+				delete(obj, "data")
+			} else {
+				obj["data"] = data
+			}
+			if stringData != nil {
+				obj["stringData"] = stringData
+			}
+		}
+		if stringData, ok := obj["stringData"]; ok {
+			obj["stringData"] = decodeEmbeddedValues(stringData)
+		}
+	}
+	return nil
+}
+
+// decodeSecretData moves the base64 values of data that decode to texts into stringData.
+// The values of stringData are preferred like the API server does.
+func decodeSecretData(data, stringData any) (any, any) {
+	items, ok := treeItems(data)
+	if !ok {
+		return data, stringData
+	}
+	if stringData == nil {
+		stringData = yaml.MapSlice{}
+	}
+	for _, x := range items {
+		s, ok := x.Value.(string)
+		if !ok {
+			continue
+		}
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil || !utf8.Valid(b) {
+			continue
+		}
+		k := treeKeyString(x.Key)
+		data = treeDelete(data, k)
+		if _, ok := treeGet(stringData, k); !ok {
+			stringData = treeSet(stringData, k, string(b))
+		}
+	}
+	if items, ok := treeItems(stringData); ok && len(items) == 0 {
+		return data, nil
+	}
+	return data, stringData
+}
+
+func decodeEmbeddedValues(data any) any {
+	items, ok := treeItems(data)
+	if !ok {
+		return data
+	}
+	for _, x := range items {
+		s, ok := x.Value.(string)
+		if !ok {
+			continue
+		}
+		if v, ok := decodeEmbeddedDocument(s); ok {
+			data = treeSet(data, treeKeyString(x.Key), v)
+		}
+	}
+	return data
+}
+
+// decodeEmbeddedDocument decodes the string if it is a multiline YAML or a JSON of a map or a list.
+func decodeEmbeddedDocument(s string) (any, bool) {
+	t := strings.TrimSpace(s)
+	if !strings.Contains(t, "\n") && !strings.HasPrefix(t, "{") && !strings.HasPrefix(t, "[") {
+		return nil, false
+	}
+	var v any
+	if err := yaml.UnmarshalWithOptions([]byte(s), &v, yaml.UseOrderedMap()); err != nil {
+		return nil, false
+	}
+	switch v.(type) {
+	case yaml.MapSlice, []any:
+		return v, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,95 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/berquerant/k8s-object-diff-go/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataDecoder(t *testing.T) {
+	for _, tc := range []struct {
+		title    string
+		manifest string
+		want     string
+	}{
+		{
+			title: "secret",
+			manifest: `apiVersion: v1
+data:
+  binary: //79
+  password: czNjcmV0
+  username: YWRtaW4=
+kind: Secret
+metadata:
+  name: app
+stringData:
+  username: root
+`,
+			want: `apiVersion: v1
+data:
+  binary: //79
+kind: Secret
+metadata:
+  name: app
+stringData:
+  username: root
+  password: s3cret
+`,
+		},
+		{
+			title: "configmap",
+			manifest: `apiVersion: v1
+data:
+  config.yaml: |
+    server:
+      port: 8080
+  env: "KEY: value"
+  list.json: '["a", "b"]'
+  script.sh: |
+    #!/bin/sh
+    echo hello
+kind: ConfigMap
+metadata:
+  name: app
+`,
+			want: `apiVersion: v1
+data:
+  config.yaml:
+    server:
+      port: 8080
+  env: "KEY: value"
+  list.json:
+  - a
+  - b
+  script.sh: |
+    #!/bin/sh
+    echo hello
+kind: ConfigMap
+metadata:
+  name: app
+`,
+		},
+		{
+			title: "other kinds",
+			manifest: `apiVersion: v1
+data:
+  password: czNjcmV0
+kind: Other
+metadata:
+  name: app
+`,
+			want: `apiVersion: v1
+data:
+  password: czNjcmV0
+kind: Other
+metadata:
+  name: app
+`,
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			assert.Equal(t, tc.want, normalizeManifest(t, internal.NewDataDecoder(), tc.manifest))
+		})
+	}
+}
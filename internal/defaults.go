@@ -0,0 +1,218 @@
+package internal
+
+import (
+	"context"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+var _ Normalizer = &DefaultsFiller{}
+
+// DefaultsFiller fills the fields of the common built-in kinds with the defaults of the API server,
+// so that an omitted default and the explicit default compare equal.
+//
+// The defaulted fields are moved to the end of their maps in a fixed order
+// to align them regardless of whether they are given or filled.
+type DefaultsFiller struct{}
+
+func NewDefaultsFiller() *DefaultsFiller {
+	return &DefaultsFiller{}
+}
+
+func (*DefaultsFiller) Normalize(_ context.Context, obj map[string]any) error {
+	var f func(any) any
+	switch obj["kind"] {
+	case "Deployment":
+		f = defaultDeploymentSpec
+	case "StatefulSet":
+		f = defaultStatefulSetSpec
+	case "DaemonSet":
+		f = defaultDaemonSetSpec
+	case "Job":
+		f = defaultJobSpec
+	case "CronJob":
+		f = defaultCronJobSpec
+	case "Service":
+		f = defaultServiceSpec
+	case "Pod":
+		f = func(v any) any { return defaultPodSpec(v, true) }
+	default:
+		return nil
+	}
+	if spec, ok := obj["spec"]; ok {
+		obj["spec"] = f(spec)
+	}
+	return nil
+}
+
+// setDefault sets the value if the key is missing, and moves the key to the end.
+func setDefault(node any, key string, value any) any {
+	if _, ok := treeItems(node); !ok {
+		return node
+	}
+	if v, ok := treeGet(node, key); ok {
+		value = v
+	}
+	return treeSet(treeDelete(node, key), key, value)
+}
+
+// updateChild updates the value of the key if exists.
+func updateChild(node any, key string, f func(any) any) any {
+	v, ok := treeGet(node, key)
+	if !ok {
+		return node
+	}
+	return treeSet(node, key, f(v))
+}
+
+// updateList updates the elements of the list of the key if exists.
+func updateList(node any, key string, f func(any) any) any {
+	return updateChild(node, key, func(v any) any {
+		xs, ok := v.([]any)
+		if !ok {
+			return v
+		}
+		for i, x := range xs {
+			xs[i] = f(x)
+		}
+		return xs
+	})
+}
+
+func defaultRollingUpdate(strategy any, fields ...yaml.MapItem) any {
+	strategy = setDefault(strategy, "type", "RollingUpdate")
+	if !treeMatch(strategy, "type", "RollingUpdate") {
+		return strategy
+	}
+	strategy = setDefault(strategy, "rollingUpdate", yaml.MapSlice{})
+	return updateChild(strategy, "rollingUpdate", func(v any) any {
+		for _, x := range fields {
+			v = setDefault(v, treeKeyString(x.Key), x.Value)
+		}
+		return v
+	})
+}
+
+func defaultDeploymentSpec(spec any) any {
+	spec = setDefault(spec, "replicas", uint64(1))
+	spec = setDefault(spec, "revisionHistoryLimit", uint64(10))
+	spec = setDefault(spec, "progressDeadlineSeconds", uint64(600))
+	spec = setDefault(spec, "strategy", yaml.MapSlice{})
+	spec = updateChild(spec, "strategy", func(v any) any {
+		return defaultRollingUpdate(v,
+			yaml.MapItem{Key: "maxUnavailable", Value: "25%"},
+			yaml.MapItem{Key: "maxSurge", Value: "25%"},
+		)
+	})
+	return updateChild(spec, "template", defaultPodTemplate(true))
+}
+
+func defaultStatefulSetSpec(spec any) any {
+	spec = setDefault(spec, "replicas", uint64(1))
+	spec = setDefault(spec, "revisionHistoryLimit", uint64(10))
+	spec = setDefault(spec, "podManagementPolicy", "OrderedReady")
+	spec = setDefault(spec, "updateStrategy", yaml.MapSlice{})
+	spec = updateChild(spec, "updateStrategy", func(v any) any {
+		return defaultRollingUpdate(v,
+			yaml.MapItem{Key: "partition", Value: uint64(0)},
+		)
+	})
+	return updateChild(spec, "template", defaultPodTemplate(true))
+}
+
+func defaultDaemonSetSpec(spec any) any {
+	spec = setDefault(spec, "revisionHistoryLimit", uint64(10))
+	spec = setDefault(spec, "updateStrategy", yaml.MapSlice{})
+	spec = updateChild(spec, "updateStrategy", func(v any) any {
+		return defaultRollingUpdate(v,
+			yaml.MapItem{Key: "maxUnavailable", Value: uint64(1)},
+			yaml.MapItem{Key: "maxSurge", Value: uint64(0)},
+		)
+	})
+	return updateChild(spec, "template", defaultPodTemplate(true))
+}
+
+func defaultJobSpec(spec any) any {
+	_, hasParallelism := treeGet(spec, "parallelism")
+	if _, ok := treeGet(spec, "completions"); ok || !hasParallelism {
+		spec = setDefault(spec, "completions", uint64(1))
+	}
+	spec = setDefault(spec, "parallelism", uint64(1))
+	spec = setDefault(spec, "backoffLimit", uint64(6))
+	spec = setDefault(spec, "completionMode", "NonIndexed")
+	spec = setDefault(spec, "suspend", false)
+	// restartPolicy of the jobs is required
+	return updateChild(spec, "template", defaultPodTemplate(false))
+}
+
+func defaultCronJobSpec(spec any) any {
+	spec = setDefault(spec, "concurrencyPolicy", "Allow")
+	spec = setDefault(spec, "suspend", false)
+	spec = setDefault(spec, "successfulJobsHistoryLimit", uint64(3))
+	spec = setDefault(spec, "failedJobsHistoryLimit", uint64(1))
+	return updateChild(spec, "jobTemplate", func(v any) any {
+		return updateChild(v, "spec", defaultJobSpec)
+	})
+}
+
+func defaultServiceSpec(spec any) any {
+	spec = setDefault(spec, "type", "ClusterIP")
+	if treeMatch(spec, "type", "ExternalName") {
+		return spec
+	}
+	spec = setDefault(spec, "sessionAffinity", "None")
+	spec = setDefault(spec, "internalTrafficPolicy", "Cluster")
+	if treeMatch(spec, "type", "NodePort") || treeMatch(spec, "type", "LoadBalancer") {
+		spec = setDefault(spec, "externalTrafficPolicy", "Cluster")
+	}
+	return updateList(spec, "ports", func(port any) any {
+		port = setDefault(port, "protocol", "TCP")
+		if v, ok := treeGet(port, "port"); ok {
+			port = setDefault(port, "targetPort", v)
+		}
+		return port
+	})
+}
+
+func defaultPodTemplate(restartPolicy bool) func(any) any {
+	return func(template any) any {
+		return updateChild(template, "spec", func(v any) any {
+			return defaultPodSpec(v, restartPolicy)
+		})
+	}
+}
+
+func defaultPodSpec(spec any, restartPolicy bool) any {
+	spec = updateList(spec, "initContainers", defaultContainer)
+	spec = updateList(spec, "containers", defaultContainer)
+	if restartPolicy {
+		spec = setDefault(spec, "restartPolicy", "Always")
+	}
+	spec = setDefault(spec, "dnsPolicy", "ClusterFirst")
+	spec = setDefault(spec, "schedulerName", "default-scheduler")
+	return setDefault(spec, "terminationGracePeriodSeconds", uint64(30))
+}
+
+func defaultContainer(container any) any {
+	image, _ := treeGet(container, "image")
+	container = setDefault(container, "imagePullPolicy", defaultImagePullPolicy(treeKeyString(image)))
+	container = updateList(container, "ports", func(port any) any {
+		return setDefault(port, "protocol", "TCP")
+	})
+	container = setDefault(container, "terminationMessagePath", "/dev/termination-log")
+	return setDefault(container, "terminationMessagePolicy", "File")
+}
+
+// defaultImagePullPolicy returns Always if the image has the latest tag or no tag, otherwise IfNotPresent.
+func defaultImagePullPolicy(image string) string {
+	if strings.Contains(image, "@") {
+		return "IfNotPresent"
+	}
+	name := image[strings.LastIndex(image, "/")+1:]
+	_, tag, ok := strings.Cut(name, ":")
+	if !ok || tag == "latest" {
+		return "Always"
+	}
+	return "IfNotPresent"
+}
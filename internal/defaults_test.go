@@ -0,0 +1,123 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/berquerant/k8s-object-diff-go/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultsFiller(t *testing.T) {
+	normalize := func(t *testing.T, manifest string) string {
+		t.Helper()
+		return normalizeManifest(t, internal.NewDefaultsFiller(), manifest)
+	}
+
+	for _, tc := range []struct {
+		title    string
+		omitted  string
+		explicit string
+	}{
+		{
+			title: "deployment",
+			omitted: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:1.0.0
+`,
+			explicit: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  strategy:
+    rollingUpdate:
+      maxSurge: 25%
+      maxUnavailable: 25%
+    type: RollingUpdate
+  replicas: 1
+  template:
+    spec:
+      dnsPolicy: ClusterFirst
+      restartPolicy: Always
+      containers:
+      - name: app
+        image: app:1.0.0
+        imagePullPolicy: IfNotPresent
+`,
+		},
+		{
+			title: "service",
+			omitted: `apiVersion: v1
+kind: Service
+metadata:
+  name: app
+spec:
+  ports:
+  - port: 80
+`,
+			explicit: `apiVersion: v1
+kind: Service
+metadata:
+  name: app
+spec:
+  type: ClusterIP
+  sessionAffinity: None
+  ports:
+  - targetPort: 80
+    protocol: TCP
+    port: 80
+`,
+		},
+		{
+			title: "job with parallelism",
+			omitted: `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: job
+spec:
+  parallelism: 2
+`,
+			explicit: `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: job
+spec:
+  backoffLimit: 6
+  parallelism: 2
+`,
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			assert.Equal(t, normalize(t, tc.explicit), normalize(t, tc.omitted))
+		})
+	}
+
+	t.Run("other kinds", func(t *testing.T) {
+		const manifest = `apiVersion: v1
+data:
+  replicas: "3"
+kind: ConfigMap
+metadata:
+  name: cm
+`
+		assert.Equal(t, manifest, normalize(t, manifest))
+	})
+
+	t.Run("job does not fill completions", func(t *testing.T) {
+		got := normalize(t, `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: job
+spec:
+  parallelism: 2
+`)
+		assert.NotContains(t, got, "completions")
+	})
+}
@@ -124,19 +124,24 @@ func (d *ProcessDiffer) Diff(ctx context.Context, req *DiffRequest) (*DiffRespon
 	)
 }
 
-type DMPDiffer struct{}
+type DMPDiffer struct {
+	wordDiff WordDiffMode
+}
 
-func NewDMPDiffer() *DMPDiffer {
-	return &DMPDiffer{}
+func NewDMPDiffer(wordDiff WordDiffMode) *DMPDiffer {
+	return &DMPDiffer{
+		wordDiff: wordDiff,
+	}
 }
 
 var _ Differ = &DMPDiffer{}
 
-func (*DMPDiffer) Diff(ctx context.Context, req *DiffRequest) (*DiffResponse, error) {
+func (d *DMPDiffer) Diff(ctx context.Context, req *DiffRequest) (*DiffResponse, error) {
 	dmp := &DMP{
 		LeftLabel:  req.LeftLabel,
 		RightLabel: req.RightLabel,
 		Context:    req.Context,
+		WordDiff:   d.wordDiff,
 	}
 	diff, err := dmp.Diff(req.Left, req.Right)
 	if err != nil {
@@ -158,6 +163,10 @@ const (
 	DiffTypeAdd
 	DiffTypeChange
 	DiffTypeDestroy
+	// DiffTypeRename is a change of the name of the object.
+	DiffTypeRename
+	// DiffTypeMove is a change of the namespace of the object.
+	DiffTypeMove
 )
 
 func (t DiffType) String() string {
@@ -170,6 +179,10 @@ func (t DiffType) String() string {
 		return "change"
 	case DiffTypeDestroy:
 		return "destroy"
+	case DiffTypeRename:
+		return "rename"
+	case DiffTypeMove:
+		return "move"
 	default:
 		return "unknown"
 	}
@@ -193,19 +206,40 @@ func newDiffHeader(name, objectID string, color bool) string {
 	return x
 }
 
+// DiffLabels names the sides of the object diffs.
+type DiffLabels struct {
+	Left  string
+	Right string
+	// LeftSource prefers the source of the left object to Left.
+	LeftSource bool
+	// RightSource prefers the source of the right object to Right.
+	RightSource bool
+}
+
+// Of returns the labels of the sides of the pair.
+func (l *DiffLabels) Of(pair *ObjectPair) (string, string) {
+	left, right := l.Left, l.Right
+	if x := pair.Left; l.LeftSource && x != nil && x.Source != "" {
+		left = x.Location()
+	}
+	if x := pair.Right; l.RightSource && x != nil && x.Source != "" {
+		right = x.Location()
+	}
+	return left, right
+}
+
 var _ ObjectDiffer = &ObjectDiffBuilder{}
 
 func NewObjectDiffBuilder(
 	differ Differ,
-	left, right string,
+	labels *DiffLabels,
 	diffContext int,
 	color bool,
 
 ) *ObjectDiffBuilder {
 	return &ObjectDiffBuilder{
 		differ:      differ,
-		left:        left,
-		right:       right,
+		labels:      labels,
 		color:       color,
 		diffContext: diffContext,
 	}
@@ -213,8 +247,7 @@ func NewObjectDiffBuilder(
 
 type ObjectDiffBuilder struct {
 	differ      Differ
-	left        string
-	right       string
+	labels      *DiffLabels
 	diffContext int
 	color       bool
 }
@@ -228,40 +261,48 @@ func (d *ObjectDiffBuilder) ObjectDiff(ctx context.Context, pair *ObjectPair) (*
 		rightBody = x.Body
 	}
 
+	leftLabel, rightLabel := d.labels.Of(pair)
 	diff, err := d.differ.Diff(ctx, &DiffRequest{
 		Left:       leftBody,
 		Right:      rightBody,
-		LeftLabel:  newDiffHeader(d.left, pair.ID, d.color),
-		RightLabel: newDiffHeader(d.right, pair.ID, d.color),
+		LeftLabel:  newDiffHeader(leftLabel, pair.LeftID(), d.color),
+		RightLabel: newDiffHeader(rightLabel, pair.ID, d.color),
 		Color:      d.color,
 		Context:    d.diffContext,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get diff: id=%s: %w", pair.ID, err)
+		return nil, fmt.Errorf("failed to get diff: id=%s: %w", pair, err)
 	}
+	return newObjectDiff(pair, diff.Diff), nil
+}
 
-	var diffType DiffType
+// ChangeTypeOf returns the type of the change of the pair, assuming the objects differ.
+func ChangeTypeOf(pair *ObjectPair) DiffType {
 	switch {
-	case diff.Diff == "":
-		diffType = DiffTypeUnchange
 	case pair.Left == nil && pair.Right != nil:
-		diffType = DiffTypeAdd
+		return DiffTypeAdd
 	case pair.Left != nil && pair.Right == nil:
-		diffType = DiffTypeDestroy
+		return DiffTypeDestroy
+	case pair.PrevID != "" && pair.Left.Header.Metadata.Namespace != pair.Right.Header.Metadata.Namespace:
+		return DiffTypeMove
+	case pair.PrevID != "":
+		return DiffTypeRename
 	default:
-		diffType = DiffTypeChange
+		return DiffTypeChange
 	}
+}
 
-	if diff.Diff == "" {
+func newObjectDiff(pair *ObjectPair, diff string) *ObjectDiff {
+	if diff == "" {
 		return &ObjectDiff{
 			Pair: pair,
-			Type: diffType,
-		}, nil
+			Type: DiffTypeUnchange,
+		}
 	}
 
 	return &ObjectDiff{
 		Pair: pair,
-		Diff: diff.Diff,
-		Type: diffType,
-	}, nil
+		Diff: diff,
+		Type: ChangeTypeOf(pair),
+	}
 }
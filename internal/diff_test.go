@@ -113,7 +113,10 @@ func TestProcessObjectDiffBuilder(t *testing.T) {
 
 			x := internal.NewObjectDiffBuilder(
 				internal.NewProcessDiffer(command, tc.args),
-				leftFile, rightFile,
+				&internal.DiffLabels{
+					Left:  leftFile,
+					Right: rightFile,
+				},
 				diffContext,
 				tc.color,
 			)
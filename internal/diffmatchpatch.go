@@ -161,6 +161,29 @@ func (p *DMPPatch) IntoString(color bool) string {
 	return p.header(color) + strings.Join(xs, "")
 }
 
+// IntoWordDiffString is IntoString with the changed spans of the paired deleted and inserted lines highlighted.
+func (p *DMPPatch) IntoWordDiffString(color bool, mode WordDiffMode) string {
+	if mode == WordDiffNone {
+		return p.IntoString(color)
+	}
+	var b strings.Builder
+	b.WriteString(p.header(color))
+	for i := 0; i < len(p.Hunks); i++ {
+		h := p.Hunks[i]
+		if h.Op == DMPOpEqual || i+1 == len(p.Hunks) || p.Hunks[i+1].Op == DMPOpEqual {
+			b.WriteString(h.IntoString(color))
+			continue
+		}
+		deleted, inserted := h, p.Hunks[i+1]
+		if h.Op == DMPOpInsert {
+			deleted, inserted = inserted, deleted
+		}
+		b.WriteString(wordDiffString(diffViewLines(deleted.Body), diffViewLines(inserted.Body), color, mode))
+		i++
+	}
+	return b.String()
+}
+
 func hunksToDebugString(hunks []*DMPHunk) string {
 	ss := make([]string, len(hunks))
 	for i, h := range hunks {
@@ -175,6 +198,7 @@ type DMPResult struct {
 	LeftLabel  string
 	RightLabel string
 	Patches    []*DMPPatch
+	WordDiff   WordDiffMode
 }
 
 func (r *DMPResult) header(color bool) string {
@@ -190,7 +214,7 @@ func (r *DMPResult) header(color bool) string {
 func (r *DMPResult) IntoString(color bool) string {
 	xs := make([]string, len(r.Patches))
 	for i, x := range r.Patches {
-		xs[i] = x.IntoString(color)
+		xs[i] = x.IntoWordDiffString(color, r.WordDiff)
 	}
 	return r.header(color) + strings.Join(xs, "")
 }
@@ -207,6 +231,7 @@ type DMP struct {
 	LeftLabel  string
 	RightLabel string
 	Context    int
+	WordDiff   WordDiffMode
 }
 
 var ErrDMPNoDiff = errors.New("DMPNoDiff")
@@ -244,6 +269,7 @@ func (p *DMP) Diff(left, right string) (*DMPResult, error) {
 		LeftLabel:  p.LeftLabel,
 		RightLabel: p.RightLabel,
 		Patches:    patches,
+		WordDiff:   p.WordDiff,
 	}, nil
 }
 
@@ -0,0 +1,153 @@
+package internal
+
+import (
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// DiffSegment is a part of a line.
+// Changed is true if the part differs from the paired line.
+type DiffSegment struct {
+	Text    string
+	Changed bool
+}
+
+// DiffLine is a line of a patch.
+// The number of the absent side is 0.
+type DiffLine struct {
+	Op          DMPOp
+	LeftNumber  int
+	RightNumber int
+	Segments    []*DiffSegment
+}
+
+func (l *DiffLine) Text() string {
+	var b strings.Builder
+	for _, s := range l.Segments {
+		b.WriteString(s.Text)
+	}
+	return b.String()
+}
+
+// DiffRow is a row of the side-by-side view.
+// Left or Right is nil if the line has no counterpart.
+type DiffRow struct {
+	Left  *DiffLine
+	Right *DiffLine
+}
+
+// DiffView is a patch laid out for display.
+type DiffView struct {
+	Header string
+	// Lines is the unified view.
+	Lines []*DiffLine
+	// Rows is the side-by-side view.
+	Rows []*DiffRow
+}
+
+// NewDiffView lays out the patch.
+// The deleted lines and the inserted lines that follow are paired in order,
+// and their differences are highlighted by character.
+func NewDiffView(patch *DMPPatch) *DiffView {
+	v := &DiffView{
+		Header: strings.TrimSuffix(patch.header(false), "\n"),
+	}
+	var (
+		leftNumber  = patch.LeftStart
+		rightNumber = patch.RightStart
+		newLine     = func(op DMPOp, text string) *DiffLine {
+			x := &DiffLine{
+				Op:       op,
+				Segments: []*DiffSegment{{Text: text}},
+			}
+			if op != DMPOpInsert {
+				x.LeftNumber = leftNumber
+				leftNumber++
+			}
+			if op != DMPOpDelete {
+				x.RightNumber = rightNumber
+				rightNumber++
+			}
+			return x
+		}
+	)
+	for i := 0; i < len(patch.Hunks); i++ {
+		h := patch.Hunks[i]
+		if h.Op == DMPOpEqual {
+			for _, s := range diffViewLines(h.Body) {
+				x := newLine(DMPOpEqual, s)
+				v.Lines = append(v.Lines, x)
+				v.Rows = append(v.Rows, &DiffRow{Left: x, Right: x})
+			}
+			continue
+		}
+
+		var deleted, inserted []string
+		if h.Op == DMPOpDelete {
+			deleted = diffViewLines(h.Body)
+		} else {
+			inserted = diffViewLines(h.Body)
+		}
+		if i+1 < len(patch.Hunks) {
+			switch next := patch.Hunks[i+1]; {
+			case h.Op == DMPOpDelete && next.Op == DMPOpInsert:
+				inserted = diffViewLines(next.Body)
+				i++
+			case h.Op == DMPOpInsert && next.Op == DMPOpDelete:
+				deleted = diffViewLines(next.Body)
+				i++
+			}
+		}
+
+		lefts := make([]*DiffLine, len(deleted))
+		for j, s := range deleted {
+			lefts[j] = newLine(DMPOpDelete, s)
+		}
+		rights := make([]*DiffLine, len(inserted))
+		for j, s := range inserted {
+			rights[j] = newLine(DMPOpInsert, s)
+		}
+		v.Lines = append(v.Lines, lefts...)
+		v.Lines = append(v.Lines, rights...)
+		for j := range max(len(lefts), len(rights)) {
+			row := &DiffRow{}
+			if j < len(lefts) {
+				row.Left = lefts[j]
+			}
+			if j < len(rights) {
+				row.Right = rights[j]
+			}
+			if row.Left != nil && row.Right != nil {
+				row.Left.Segments, row.Right.Segments = diffSegments(deleted[j], inserted[j])
+			}
+			v.Rows = append(v.Rows, row)
+		}
+	}
+	return v
+}
+
+func diffViewLines(body string) []string {
+	if body == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(body, "\n"), "\n")
+}
+
+// diffSegments highlights the characters that differ between the lines.
+func diffSegments(left, right string) ([]*DiffSegment, []*DiffSegment) {
+	diffs := intraLineDiffs(left, right, WordDiffChar)
+	var lefts, rights []*DiffSegment
+	for _, d := range diffs {
+		switch d.Type {
+		case diffmatchpatch.DiffEqual:
+			lefts = append(lefts, &DiffSegment{Text: d.Text})
+			rights = append(rights, &DiffSegment{Text: d.Text})
+		case diffmatchpatch.DiffDelete:
+			lefts = append(lefts, &DiffSegment{Text: d.Text, Changed: true})
+		case diffmatchpatch.DiffInsert:
+			rights = append(rights, &DiffSegment{Text: d.Text, Changed: true})
+		}
+	}
+	return lefts, rights
+}
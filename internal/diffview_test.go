@@ -0,0 +1,81 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/berquerant/k8s-object-diff-go/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDiffView(t *testing.T) {
+	const (
+		left = `a: 1
+b: 2
+c: 3
+d: 4
+`
+		right = `a: 1
+b: 20
+c: 3
+e: 5
+f: 6
+`
+	)
+	result, err := (&internal.DMP{Context: 1}).Diff(left, right)
+	if !assert.Nil(t, err) {
+		return
+	}
+	if !assert.Len(t, result.Patches, 1) {
+		return
+	}
+	v := internal.NewDiffView(result.Patches[0])
+	assert.Equal(t, "@@ -1,4 +1,5 @@", v.Header)
+
+	type line struct {
+		op          internal.DMPOp
+		left, right int
+		text        string
+	}
+	toLine := func(x *internal.DiffLine) *line {
+		if x == nil {
+			return nil
+		}
+		return &line{op: x.Op, left: x.LeftNumber, right: x.RightNumber, text: x.Text()}
+	}
+
+	t.Run("unified", func(t *testing.T) {
+		got := make([]*line, len(v.Lines))
+		for i, x := range v.Lines {
+			got[i] = toLine(x)
+		}
+		assert.Equal(t, []*line{
+			{op: internal.DMPOpEqual, left: 1, right: 1, text: "a: 1"},
+			{op: internal.DMPOpDelete, left: 2, text: "b: 2"},
+			{op: internal.DMPOpInsert, right: 2, text: "b: 20"},
+			{op: internal.DMPOpEqual, left: 3, right: 3, text: "c: 3"},
+			{op: internal.DMPOpDelete, left: 4, text: "d: 4"},
+			{op: internal.DMPOpInsert, right: 4, text: "e: 5"},
+			{op: internal.DMPOpInsert, right: 5, text: "f: 6"},
+		}, got)
+	})
+
+	t.Run("side by side", func(t *testing.T) {
+		got := make([][2]*line, len(v.Rows))
+		for i, x := range v.Rows {
+			got[i] = [2]*line{toLine(x.Left), toLine(x.Right)}
+		}
+		assert.Equal(t, [][2]*line{
+			{{op: internal.DMPOpEqual, left: 1, right: 1, text: "a: 1"}, {op: internal.DMPOpEqual, left: 1, right: 1, text: "a: 1"}},
+			{{op: internal.DMPOpDelete, left: 2, text: "b: 2"}, {op: internal.DMPOpInsert, right: 2, text: "b: 20"}},
+			{{op: internal.DMPOpEqual, left: 3, right: 3, text: "c: 3"}, {op: internal.DMPOpEqual, left: 3, right: 3, text: "c: 3"}},
+			{{op: internal.DMPOpDelete, left: 4, text: "d: 4"}, {op: internal.DMPOpInsert, right: 4, text: "e: 5"}},
+			{nil, {op: internal.DMPOpInsert, right: 5, text: "f: 6"}},
+		}, got)
+	})
+
+	t.Run("intra-line", func(t *testing.T) {
+		row := v.Rows[1]
+		assert.Equal(t, []*internal.DiffSegment{{Text: "b: 2"}}, row.Left.Segments)
+		assert.Equal(t, []*internal.DiffSegment{{Text: "b: 2"}, {Text: "0", Changed: true}}, row.Right.Segments)
+	})
+}
@@ -0,0 +1,102 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// IgnoreRule removes the fields pointed by the Path from objects.
+// If Kind is not empty, the rule applies only to the objects of the kind.
+type IgnoreRule struct {
+	Kind string
+	Path Path
+}
+
+var (
+	ErrInvalidIgnoreRule = errors.New("InvalidIgnoreRule")
+
+	ignoreRuleKindRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+)
+
+// ParseIgnoreRule parses a rule in the form of [KIND:]PATH.
+//
+//	status
+//	metadata.annotations["checksum/config"]
+//	Deployment:spec.replicas
+func ParseIgnoreRule(s string) (*IgnoreRule, error) {
+	var kind string
+	if k, p, ok := strings.Cut(s, ":"); ok && ignoreRuleKindRegexp.MatchString(k) {
+		kind = k
+		s = p
+	}
+	path, err := ParsePath(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIgnoreRule, err)
+	}
+	return &IgnoreRule{
+		Kind: kind,
+		Path: path,
+	}, nil
+}
+
+func (r *IgnoreRule) String() string {
+	if r.Kind == "" {
+		return r.Path.String()
+	}
+	return r.Kind + ":" + r.Path.String()
+}
+
+func (r *IgnoreRule) isTarget(obj map[string]any) bool {
+	if r.Kind == "" {
+		return true
+	}
+	kind, _ := obj["kind"].(string)
+	return kind == r.Kind
+}
+
+func (r *IgnoreRule) apply(obj map[string]any) {
+	if r.isTarget(obj) {
+		_ = treeRemove(obj, r.Path)
+	}
+}
+
+var _ Normalizer = IgnoreRules(nil)
+
+type IgnoreRules []*IgnoreRule
+
+func ParseIgnoreRules(xs []string) (IgnoreRules, error) {
+	rules := make(IgnoreRules, len(xs))
+	for i, x := range xs {
+		r, err := ParseIgnoreRule(x)
+		if err != nil {
+			return nil, err
+		}
+		rules[i] = r
+	}
+	return rules, nil
+}
+
+func (rs IgnoreRules) Normalize(_ context.Context, obj map[string]any) error {
+	for _, r := range rs {
+		r.apply(obj)
+	}
+	return nil
+}
+
+// ServerSideFields are the fields populated by the API server.
+var ServerSideFields = []string{
+	"metadata.managedFields",
+	"metadata.resourceVersion",
+	"metadata.uid",
+	"metadata.selfLink",
+	"metadata.creationTimestamp",
+	"metadata.generation",
+	`metadata.annotations["kubectl.kubernetes.io/last-applied-configuration"]`,
+	`metadata.annotations["deployment.kubernetes.io/revision"]`,
+	"spec.template.metadata.creationTimestamp",
+	"spec.jobTemplate.spec.template.metadata.creationTimestamp",
+	"status",
+}
@@ -0,0 +1,198 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/berquerant/k8s-object-diff-go/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIgnoreRule(t *testing.T) {
+	for _, tc := range []struct {
+		title string
+		rule  string
+		kind  string
+		path  string
+		err   bool
+	}{
+		{
+			title: "path",
+			rule:  "status",
+			path:  "status",
+		},
+		{
+			title: "kind",
+			rule:  "Deployment:spec.replicas",
+			kind:  "Deployment",
+			path:  "spec.replicas",
+		},
+		{
+			title: "colon in key",
+			rule:  `metadata.annotations["a:b"]`,
+			path:  `metadata.annotations["a:b"]`,
+		},
+		{
+			title: "kind only",
+			rule:  "Deployment:",
+			err:   true,
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			got, err := internal.ParseIgnoreRule(tc.rule)
+			if tc.err {
+				assert.ErrorIs(t, err, internal.ErrInvalidIgnoreRule)
+				return
+			}
+			if !assert.Nil(t, err) {
+				return
+			}
+			assert.Equal(t, tc.kind, got.Kind)
+			assert.Equal(t, tc.path, got.Path.String())
+		})
+	}
+}
+
+func TestIgnoreRules(t *testing.T) {
+	const manifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  labels:
+    app: app
+    helm.sh/chart: app-1.0.0
+  annotations:
+    checksum/config: abcdef
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:1
+        env:
+        - name: A
+          value: a
+      - name: sidecar
+        image: sidecar:1
+status:
+  replicas: 1
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+spec:
+  replicas: 1
+`
+	for _, tc := range []struct {
+		title string
+		rules []string
+		want  []string
+	}{
+		{
+			title: "no rules",
+			want: []string{
+				`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  labels:
+    app: app
+    helm.sh/chart: app-1.0.0
+  annotations:
+    checksum/config: abcdef
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:1
+        env:
+        - name: A
+          value: a
+      - name: sidecar
+        image: sidecar:1
+status:
+  replicas: 1
+`,
+				`apiVersion: v1
+kind: Service
+metadata:
+  name: app
+spec:
+  replicas: 1
+`,
+			},
+		},
+		{
+			title: "volatile fields",
+			rules: []string{
+				"status",
+				`metadata.annotations["checksum/config"]`,
+				`metadata.labels["helm.sh/chart"]`,
+				"Deployment:spec.replicas",
+				"spec.template.spec.containers[*].env",
+				"spec.template.spec.containers[name=sidecar]",
+			},
+			want: []string{
+				`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  labels:
+    app: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:1
+`,
+				`apiVersion: v1
+kind: Service
+metadata:
+  name: app
+spec:
+  replicas: 1
+`,
+			},
+		},
+		{
+			title: "wildcard key and index",
+			rules: []string{
+				"metadata.*",
+				"spec.template.spec.containers[1]",
+				"$.status",
+			},
+			want: []string{
+				`apiVersion: apps/v1
+kind: Deployment
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:1
+        env:
+        - name: A
+          value: a
+`,
+				`apiVersion: v1
+kind: Service
+spec:
+  replicas: 1
+`,
+			},
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			rules, err := internal.ParseIgnoreRules(tc.rules)
+			if !assert.Nil(t, err) {
+				return
+			}
+			assert.Equal(t, tc.want, normalizeManifests(t, rules, manifest))
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// JSONPatchOperation is an operation of RFC 6902 JSON Patch.
+type JSONPatchOperation struct {
+	Op    string
+	Path  string
+	Value any
+}
+
+const (
+	JSONPatchAdd     = "add"
+	JSONPatchRemove  = "remove"
+	JSONPatchReplace = "replace"
+)
+
+func (o *JSONPatchOperation) MarshalJSON() ([]byte, error) {
+	if o.Op == JSONPatchRemove {
+		return marshalJSON(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{
+			Op:   o.Op,
+			Path: o.Path,
+		})
+	}
+	// value is required even if null
+	return marshalJSON(struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value any    `json:"value"`
+	}{
+		Op:    o.Op,
+		Path:  o.Path,
+		Value: o.Value,
+	})
+}
+
+// JSONPointer returns the RFC 6901 JSON Pointer of the path of keys and indexes.
+func JSONPointer(path Path) string {
+	var b strings.Builder
+	for _, e := range path {
+		b.WriteString("/")
+		switch e.Type {
+		case PathIndex:
+			b.WriteString(strconv.Itoa(e.Index))
+		default:
+			b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(e.Key))
+		}
+	}
+	return b.String()
+}
+
+// JSONPatch returns the operations that turn left into right.
+// Map entries are compared by key and list elements by index.
+// A nil side means an absent object, then the whole document is added or removed.
+func JSONPatch(left, right any) []*JSONPatchOperation {
+	switch {
+	case left == nil && right == nil:
+		return nil
+	case left == nil:
+		return []*JSONPatchOperation{{Op: JSONPatchAdd, Path: "", Value: JSONValue(right)}}
+	case right == nil:
+		return []*JSONPatchOperation{{Op: JSONPatchRemove, Path: ""}}
+	}
+	d := &jsonPatchDiffer{}
+	d.diff(nil, left, right)
+	return d.ops
+}
+
+type jsonPatchDiffer struct {
+	ops []*JSONPatchOperation
+}
+
+func (d *jsonPatchDiffer) add(op string, path Path, value any) {
+	x := &JSONPatchOperation{
+		Op:   op,
+		Path: JSONPointer(path),
+	}
+	if op != JSONPatchRemove {
+		x.Value = JSONValue(value)
+	}
+	d.ops = append(d.ops, x)
+}
+
+func (d *jsonPatchDiffer) diff(path Path, left, right any) {
+	leftItems, leftIsMap := treeItems(left)
+	rightItems, rightIsMap := treeItems(right)
+	if leftIsMap && rightIsMap {
+		for _, x := range leftItems {
+			k := treeKeyString(x.Key)
+			p := path.Append(&PathElement{Type: PathKey, Key: k})
+			if y, ok := treeGet(right, k); ok {
+				d.diff(p, x.Value, y)
+				continue
+			}
+			d.add(JSONPatchRemove, p, nil)
+		}
+		for _, x := range rightItems {
+			k := treeKeyString(x.Key)
+			if _, ok := treeGet(left, k); !ok {
+				d.add(JSONPatchAdd, path.Append(&PathElement{Type: PathKey, Key: k}), x.Value)
+			}
+		}
+		return
+	}
+
+	leftList, leftIsList := left.([]any)
+	rightList, rightIsList := right.([]any)
+	if leftIsList && rightIsList {
+		n := min(len(leftList), len(rightList))
+		for i := range n {
+			d.diff(path.Append(&PathElement{Type: PathIndex, Index: i}), leftList[i], rightList[i])
+		}
+		// remove from the tail so that the indexes stay valid
+		for i := len(leftList) - 1; i >= n; i-- {
+			d.add(JSONPatchRemove, path.Append(&PathElement{Type: PathIndex, Index: i}), nil)
+		}
+		for i := n; i < len(rightList); i++ {
+			d.add(JSONPatchAdd, path.Append(&PathElement{Type: PathIndex, Index: i}), rightList[i])
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(left, right) {
+		d.add(JSONPatchReplace, path, right)
+	}
+}
+
+var _ ObjectDiffer = &JSONPatchObjectDiffer{}
+
+// JSONPatchObjectDiffer reports the JSON Patch that turns the left object into the right object as Diff.
+type JSONPatchObjectDiffer struct{}
+
+func NewJSONPatchObjectDiffer() *JSONPatchObjectDiffer {
+	return &JSONPatchObjectDiffer{}
+}
+
+func (*JSONPatchObjectDiffer) ObjectDiff(_ context.Context, pair *ObjectPair) (*ObjectDiff, error) {
+	var left, right any
+	if x := pair.Left; x != nil {
+		left = x.Value
+	}
+	if x := pair.Right; x != nil {
+		right = x.Value
+	}
+
+	ops := JSONPatch(left, right)
+	if len(ops) == 0 {
+		return newObjectDiff(pair, ""), nil
+	}
+	b, err := marshalJSON(ops)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get json patch: id=%s: %w", pair, err)
+	}
+	return newObjectDiff(pair, string(b)), nil
+}
+
+// marshalJSON marshals v without escaping HTML characters like > of the object IDs.
+func marshalJSON(v any) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
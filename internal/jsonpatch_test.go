@@ -0,0 +1,102 @@
+package internal_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/berquerant/k8s-object-diff-go/internal"
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONPointer(t *testing.T) {
+	p, err := internal.ParsePath(`metadata.annotations["example.com/a~b"]`)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "/metadata/annotations/example.com~1a~0b", internal.JSONPointer(p))
+
+	p, err = internal.ParsePath("spec.containers[1].image")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "/spec/containers/1/image", internal.JSONPointer(p))
+}
+
+func TestJSONPatch(t *testing.T) {
+	for _, tc := range []struct {
+		title string
+		left  any
+		right any
+		want  string
+	}{
+		{
+			title: "same",
+			left:  map[string]any{"a": uint64(1)},
+			right: map[string]any{"a": uint64(1)},
+			want:  `null`,
+		},
+		{
+			title: "add object",
+			right: map[string]any{"a": yaml.MapSlice{{Key: "b", Value: "c"}}},
+			want:  `[{"op":"add","path":"","value":{"a":{"b":"c"}}}]`,
+		},
+		{
+			title: "remove object",
+			left:  map[string]any{"a": uint64(1)},
+			want:  `[{"op":"remove","path":""}]`,
+		},
+		{
+			title: "map",
+			left: map[string]any{
+				"a": uint64(1),
+				"b": yaml.MapSlice{{Key: "c", Value: "d"}, {Key: "x/y", Value: "z"}},
+			},
+			right: map[string]any{
+				"a": "1",
+				"b": yaml.MapSlice{{Key: "c", Value: "d"}, {Key: "e", Value: nil}},
+			},
+			want: `[{"op":"replace","path":"/a","value":"1"},{"op":"remove","path":"/b/x~1y"},{"op":"add","path":"/b/e","value":null}]`,
+		},
+		{
+			title: "shrink list",
+			left:  map[string]any{"a": []any{"x", "y", "z"}},
+			right: map[string]any{"a": []any{"w"}},
+			want:  `[{"op":"replace","path":"/a/0","value":"w"},{"op":"remove","path":"/a/2"},{"op":"remove","path":"/a/1"}]`,
+		},
+		{
+			title: "grow list",
+			left:  map[string]any{"a": []any{"x"}},
+			right: map[string]any{"a": []any{"x", "y", "z"}},
+			want:  `[{"op":"add","path":"/a/1","value":"y"},{"op":"add","path":"/a/2","value":"z"}]`,
+		},
+		{
+			title: "type change",
+			left:  map[string]any{"a": []any{"x"}},
+			right: map[string]any{"a": yaml.MapSlice{{Key: "x", Value: "y"}}},
+			want:  `[{"op":"replace","path":"/a","value":{"x":"y"}}]`,
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			b, err := json.Marshal(internal.JSONPatch(tc.left, tc.right))
+			if !assert.Nil(t, err) {
+				return
+			}
+			assert.Equal(t, tc.want, string(b))
+		})
+	}
+}
+
+func TestJSONPatchObjectDiffer(t *testing.T) {
+	pair := &internal.ObjectPair{
+		ID:    "v1>ConfigMap>>cm",
+		Left:  &internal.Object{Value: map[string]any{"a": "x"}},
+		Right: &internal.Object{Value: map[string]any{"a": "<b & c>"}},
+	}
+	d, err := internal.NewJSONPatchObjectDiffer().ObjectDiff(context.TODO(), pair)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, `[{"op":"replace","path":"/a","value":"<b & c>"}]`, d.Diff, "not escaped like -o json")
+}
@@ -0,0 +1,84 @@
+package internal
+
+import (
+	"context"
+	"regexp"
+)
+
+// kustomize appends the hash of the content to the names of the generated ConfigMaps and Secrets,
+// 10 characters encoded from hex with 0, 1, 3, a, e replaced by g, h, k, m, t.
+var hashSuffixRegexp = regexp.MustCompile(`-[bcdfghkmt24-9]{10}$`)
+
+// StripHashSuffix removes the kustomize hash suffix from the name, e.g. app-config-7h2kf9t4m8 to app-config.
+func StripHashSuffix(name string) string {
+	return hashSuffixRegexp.ReplaceAllString(name, "")
+}
+
+// hashSuffixRefFields are the fields that refer to ConfigMaps and Secrets by name,
+// from envFrom, env.valueFrom, volumes and projected volume sources.
+var hashSuffixRefFields = map[string][]string{
+	"configMapRef":    {"name"},
+	"secretRef":       {"name"},
+	"configMapKeyRef": {"name"},
+	"secretKeyRef":    {"name"},
+	"configMap":       {"name"},
+	"secret":          {"secretName", "name"},
+}
+
+var _ Normalizer = &HashSuffixStripper{}
+
+// HashSuffixStripper removes the kustomize hash suffixes from the names of ConfigMaps and Secrets
+// and from the references to them, so that a generated object is paired across content changes.
+type HashSuffixStripper struct{}
+
+func NewHashSuffixStripper() *HashSuffixStripper {
+	return &HashSuffixStripper{}
+}
+
+func (s *HashSuffixStripper) Normalize(_ context.Context, obj map[string]any) error {
+	switch obj["kind"] {
+	case "ConfigMap", "Secret":
+		if metadata, ok := obj["metadata"]; ok {
+			obj["metadata"] = stripHashSuffixField(metadata, "name")
+		}
+	}
+	for k, v := range obj {
+		obj[k] = s.strip(v)
+	}
+	return nil
+}
+
+func (s *HashSuffixStripper) strip(v any) any {
+	if xs, ok := v.([]any); ok {
+		for i, x := range xs {
+			xs[i] = s.strip(x)
+		}
+		return xs
+	}
+
+	items, ok := treeItems(v)
+	if !ok {
+		return v
+	}
+	for _, x := range items {
+		k := treeKeyString(x.Key)
+		y := s.strip(x.Value)
+		for _, field := range hashSuffixRefFields[k] {
+			y = stripHashSuffixField(y, field)
+		}
+		v = treeSet(v, k, y)
+	}
+	return v
+}
+
+func stripHashSuffixField(v any, field string) any {
+	x, ok := treeGet(v, field)
+	if !ok {
+		return v
+	}
+	name, ok := x.(string)
+	if !ok {
+		return v
+	}
+	return treeSet(v, field, StripHashSuffix(name))
+}
@@ -0,0 +1,102 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/berquerant/k8s-object-diff-go/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripHashSuffix(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		want string
+	}{
+		{name: "app-config-7h2kf9t4m8", want: "app-config"},
+		{name: "app-config", want: "app-config"},
+		{name: "app-config-abcdefghij", want: "app-config-abcdefghij"},
+		{name: "app-7h2kf9t4m8-config", want: "app-7h2kf9t4m8-config"},
+		{name: "7h2kf9t4m8", want: "7h2kf9t4m8"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, internal.StripHashSuffix(tc.name))
+		})
+	}
+}
+
+func TestHashSuffixStripper(t *testing.T) {
+	const manifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config-7h2kf9t4m8
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: app-7h2kf9t4m8
+spec:
+  containers:
+  - name: app
+    envFrom:
+    - configMapRef:
+        name: app-config-7h2kf9t4m8
+    - secretRef:
+        name: app-secret-bt5m8c9dgk
+    env:
+    - name: A
+      valueFrom:
+        configMapKeyRef:
+          name: app-config-7h2kf9t4m8
+          key: A
+  volumes:
+  - name: secret
+    secret:
+      secretName: app-secret-bt5m8c9dgk
+  - name: projected
+    projected:
+      sources:
+      - configMap:
+          name: app-config-7h2kf9t4m8
+      - secret:
+          name: app-secret-bt5m8c9dgk
+`
+	want := []string{
+		`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+`,
+		`apiVersion: v1
+kind: Pod
+metadata:
+  name: app-7h2kf9t4m8
+spec:
+  containers:
+  - name: app
+    envFrom:
+    - configMapRef:
+        name: app-config
+    - secretRef:
+        name: app-secret
+    env:
+    - name: A
+      valueFrom:
+        configMapKeyRef:
+          name: app-config
+          key: A
+  volumes:
+  - name: secret
+    secret:
+      secretName: app-secret
+  - name: projected
+    projected:
+      sources:
+      - configMap:
+          name: app-config
+      - secret:
+          name: app-secret
+`,
+	}
+
+	assert.Equal(t, want, normalizeManifests(t, internal.NewHashSuffixStripper(), manifest))
+}
@@ -0,0 +1,217 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+type labelOperator int
+
+const (
+	labelOperatorExists labelOperator = iota
+	labelOperatorDoesNotExist
+	labelOperatorEquals
+	labelOperatorNotEquals
+	labelOperatorIn
+	labelOperatorNotIn
+)
+
+type labelRequirement struct {
+	key      string
+	operator labelOperator
+	values   []string
+}
+
+func (r *labelRequirement) match(labels map[string]string) bool {
+	v, ok := labels[r.key]
+	switch r.operator {
+	case labelOperatorExists:
+		return ok
+	case labelOperatorDoesNotExist:
+		return !ok
+	case labelOperatorEquals, labelOperatorIn:
+		return ok && slices.Contains(r.values, v)
+	case labelOperatorNotEquals, labelOperatorNotIn:
+		return !ok || !slices.Contains(r.values, v)
+	default:
+		return false
+	}
+}
+
+// LabelSelector matches the labels like the label selectors of Kubernetes.
+// All the requirements should match.
+type LabelSelector struct {
+	requirements []*labelRequirement
+	raw          string
+}
+
+var (
+	ErrInvalidLabelSelector = errors.New("InvalidLabelSelector")
+
+	labelSelectorKeyRegexp   = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_./]*[A-Za-z0-9])?$`)
+	labelSelectorValueRegexp = regexp.MustCompile(`^([A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?)?$`)
+	labelSelectorSetRegexp   = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\((.*)\)$`)
+)
+
+// ParseLabelSelector parses a selector in the syntax of the label selectors of Kubernetes.
+// The empty selector matches everything.
+//
+//	app=web,tier!=cache
+//	environment in (production,staging)
+//	team notin (infra)
+//	app.kubernetes.io/part-of
+//	!deprecated
+func ParseLabelSelector(s string) (*LabelSelector, error) {
+	return parseLabelSelector(s, true)
+}
+
+// ParseAnnotationSelector parses a selector in the syntax of the label selectors of Kubernetes,
+// but allows any values without commas and parentheses.
+func ParseAnnotationSelector(s string) (*LabelSelector, error) {
+	return parseLabelSelector(s, false)
+}
+
+func parseLabelSelector(s string, strictValue bool) (*LabelSelector, error) {
+	var requirements []*labelRequirement
+	for _, term := range splitLabelSelector(s) {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			if strings.TrimSpace(s) == "" {
+				break
+			}
+			return nil, fmt.Errorf("%w: %s: empty requirement", ErrInvalidLabelSelector, s)
+		}
+		r, err := parseLabelRequirement(term, strictValue)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidLabelSelector, s, err)
+		}
+		requirements = append(requirements, r)
+	}
+	return &LabelSelector{
+		requirements: requirements,
+		raw:          s,
+	}, nil
+}
+
+// splitLabelSelector splits s by the commas out of the parentheses.
+func splitLabelSelector(s string) []string {
+	var (
+		xs    []string
+		depth int
+		start int
+	)
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				xs = append(xs, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(xs, s[start:])
+}
+
+func parseLabelRequirement(s string, strictValue bool) (*labelRequirement, error) {
+	var r labelRequirement
+	switch {
+	case strings.HasPrefix(s, "!"):
+		r.key = strings.TrimSpace(s[1:])
+		r.operator = labelOperatorDoesNotExist
+	case labelSelectorSetRegexp.MatchString(s):
+		m := labelSelectorSetRegexp.FindStringSubmatch(s)
+		r.key = m[1]
+		r.operator = labelOperatorIn
+		if m[2] == "notin" {
+			r.operator = labelOperatorNotIn
+		}
+		for _, v := range strings.Split(m[3], ",") {
+			r.values = append(r.values, strings.TrimSpace(v))
+		}
+	case strings.Contains(s, "!="):
+		k, v, _ := strings.Cut(s, "!=")
+		r.key = strings.TrimSpace(k)
+		r.operator = labelOperatorNotEquals
+		r.values = []string{strings.TrimSpace(v)}
+	case strings.Contains(s, "="):
+		k, v, _ := strings.Cut(s, "=")
+		r.key = strings.TrimSpace(k)
+		r.operator = labelOperatorEquals
+		r.values = []string{strings.TrimSpace(strings.TrimPrefix(v, "="))}
+	default:
+		r.key = s
+		r.operator = labelOperatorExists
+	}
+
+	if !labelSelectorKeyRegexp.MatchString(r.key) {
+		return nil, fmt.Errorf("invalid key: %q", r.key)
+	}
+	for _, v := range r.values {
+		if strictValue && !labelSelectorValueRegexp.MatchString(v) {
+			return nil, fmt.Errorf("invalid value: %q", v)
+		}
+	}
+	return &r, nil
+}
+
+func (s *LabelSelector) String() string { return s.raw }
+
+// Match reports whether the labels meet all the requirements.
+func (s *LabelSelector) Match(labels map[string]string) bool {
+	for _, r := range s.requirements {
+		if !r.match(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+var _ ObjectPairer = &LabelSelectorPairer{}
+
+// LabelSelectorPairer keeps the pairs whose left or right object matches
+// both the label selector and the annotation selector.
+type LabelSelectorPairer struct {
+	pairer      ObjectPairer
+	labels      *LabelSelector
+	annotations *LabelSelector
+}
+
+// NewLabelSelectorPairer returns a new [LabelSelectorPairer].
+// nil selectors match everything.
+func NewLabelSelectorPairer(pairer ObjectPairer, labels, annotations *LabelSelector) *LabelSelectorPairer {
+	return &LabelSelectorPairer{
+		pairer:      pairer,
+		labels:      labels,
+		annotations: annotations,
+	}
+}
+
+func (p *LabelSelectorPairer) match(obj *Object) bool {
+	if obj == nil {
+		return false
+	}
+	if p.labels != nil && !p.labels.Match(obj.Header.Metadata.Labels) {
+		return false
+	}
+	if p.annotations != nil && !p.annotations.Match(obj.Header.Metadata.Annotations) {
+		return false
+	}
+	return true
+}
+
+func (p *LabelSelectorPairer) ObjectPairs() []*ObjectPair {
+	var xs []*ObjectPair
+	for _, x := range p.pairer.ObjectPairs() {
+		if p.match(x.Left) || p.match(x.Right) {
+			xs = append(xs, x)
+		}
+	}
+	return xs
+}
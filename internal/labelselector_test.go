@@ -0,0 +1,65 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/berquerant/k8s-object-diff-go/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelSelector(t *testing.T) {
+	labels := map[string]string{
+		"app.kubernetes.io/part-of": "shop",
+		"team":                      "web",
+	}
+
+	for _, tc := range []struct {
+		selector string
+		want     bool
+	}{
+		{selector: "", want: true},
+		{selector: "team=web", want: true},
+		{selector: "team==web", want: true},
+		{selector: "team=api", want: false},
+		{selector: "team!=api", want: true},
+		{selector: "tier!=cache", want: true},
+		{selector: "team in (api, web)", want: true},
+		{selector: "team in (api)", want: false},
+		{selector: "team notin (api)", want: true},
+		{selector: "tier notin (cache)", want: true},
+		{selector: "app.kubernetes.io/part-of", want: true},
+		{selector: "tier", want: false},
+		{selector: "!tier", want: true},
+		{selector: "!team", want: false},
+		{selector: "app.kubernetes.io/part-of=shop,team in (web,api),!tier", want: true},
+		{selector: "app.kubernetes.io/part-of=shop,team=api", want: false},
+	} {
+		t.Run(tc.selector, func(t *testing.T) {
+			s, err := internal.ParseLabelSelector(tc.selector)
+			if !assert.Nil(t, err) {
+				return
+			}
+			assert.Equal(t, tc.want, s.Match(labels))
+		})
+	}
+
+	for _, s := range []string{
+		"team=web,",
+		"=web",
+		"team=web app",
+		"team in (web,a b)",
+	} {
+		t.Run("invalid "+s, func(t *testing.T) {
+			_, err := internal.ParseLabelSelector(s)
+			assert.ErrorIs(t, err, internal.ErrInvalidLabelSelector)
+		})
+	}
+
+	t.Run("annotation", func(t *testing.T) {
+		s, err := internal.ParseAnnotationSelector("example.com/owner=api@example.com")
+		if !assert.Nil(t, err) {
+			return
+		}
+		assert.True(t, s.Match(map[string]string{"example.com/owner": "api@example.com"}))
+	})
+}
@@ -9,20 +9,32 @@ import (
 	"github.com/goccy/go-yaml"
 )
 
-func LoadObjects(ctx context.Context, r io.Reader, marshaler Marshaler, allowDuplicteMapKey bool) ([]*Object, error) {
+func LoadObjects(ctx context.Context, r io.Reader, marshaler Marshaler, normalizer Normalizer, allowDuplicteMapKey bool) ([]*Object, error) {
+	return LoadObjectsFunc(ctx, r, marshaler, normalizer, allowDuplicteMapKey, func(_ int, err error) error {
+		return err
+	})
+}
+
+// LoadObjectsFunc is [LoadObjects] that calls onError with the index of the document that fails to load.
+// The document is skipped if onError returns nil.
+func LoadObjectsFunc(ctx context.Context, r io.Reader, marshaler Marshaler, normalizer Normalizer, allowDuplicteMapKey bool, onError func(int, error) error) ([]*Object, error) {
 	m := NewYamlUnmarshaler(r, map[string]any{}, allowDuplicteMapKey)
-	xs, err := m.Unmarshal(ctx)
+	xs, err := m.UnmarshalDocuments(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("load objects: %w", err)
 	}
 
-	result := make([]*Object, len(xs))
+	result := make([]*Object, 0, len(xs))
 	for i, x := range xs {
-		v, err := LoadObjectFromMap(ctx, marshaler, x)
+		v, err := LoadObjectFromMap(ctx, marshaler, normalizer, x.Value)
 		if err != nil {
-			return nil, fmt.Errorf("load obejcts: index %d: %w", i, err)
+			if err := onError(i, err); err != nil {
+				return nil, fmt.Errorf("load obejcts: index %d: %w", i, err)
+			}
+			continue
 		}
-		result[i] = v
+		v.Line = x.Line
+		result = append(result, v)
 	}
 
 	return result, nil
@@ -30,7 +42,18 @@ func LoadObjects(ctx context.Context, r io.Reader, marshaler Marshaler, allowDup
 
 var ErrLoadObject = errors.New("LoadObject")
 
-func LoadObjectFromMap(ctx context.Context, marshaler Marshaler, obj map[string]any) (*Object, error) {
+// LoadObjectFromMap builds an Object from the decoded document.
+// The normalizer is applied to obj before building if not nil.
+func LoadObjectFromMap(ctx context.Context, marshaler Marshaler, normalizer Normalizer, obj map[string]any) (*Object, error) {
+	// the selectors see the labels and annotations as written
+	labels := metadataStringMap(obj, "labels")
+	annotations := metadataStringMap(obj, "annotations")
+	if normalizer != nil {
+		if err := normalizer.Normalize(ctx, obj); err != nil {
+			return nil, fmt.Errorf("failed to normalize: %w", errors.Join(err, ErrLoadObject))
+		}
+	}
+
 	getString := func(key string) (string, error) {
 		x, ok := obj[key]
 		if !ok {
@@ -109,6 +132,8 @@ func LoadObjectFromMap(ctx context.Context, marshaler Marshaler, obj map[string]
 		}
 		h.Metadata.Name = x
 	}
+	h.Metadata.Labels = labels
+	h.Metadata.Annotations = annotations
 
 	b, err := marshaler.Marshal(ctx, obj)
 	if err != nil {
@@ -118,5 +143,20 @@ func LoadObjectFromMap(ctx context.Context, marshaler Marshaler, obj map[string]
 	return &Object{
 		Header: h,
 		Body:   string(b),
+		Value:  obj,
 	}, nil
 }
+
+// metadataStringMap returns the map of the key in the metadata, nil if empty.
+func metadataStringMap(obj map[string]any, key string) map[string]string {
+	x, _ := treeGet(obj["metadata"], key)
+	items, ok := treeItems(x)
+	if !ok || len(items) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(items))
+	for _, item := range items {
+		m[treeKeyString(item.Key)] = treeKeyString(item.Value)
+	}
+	return m
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/berquerant/k8s-object-diff-go/internal"
+	"github.com/goccy/go-yaml"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -29,7 +30,7 @@ spec:
     - containerPort: 80
 `
 		r := strings.NewReader(manifest)
-		got, err := internal.LoadObjects(context.TODO(), r, internal.NewYamlMarshaler(2, true), true)
+		got, err := internal.LoadObjects(context.TODO(), r, internal.NewYamlMarshaler(2, true), nil, true)
 		if !assert.Nil(t, err) {
 			return
 		}
@@ -45,13 +46,106 @@ spec:
 				},
 			},
 			Body: manifest,
+			Value: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"metadata": yaml.MapSlice{
+					{Key: "name", Value: "nginx"},
+				},
+				"spec": yaml.MapSlice{
+					{Key: "containers", Value: []any{
+						yaml.MapSlice{
+							{Key: "name", Value: "nginx"},
+							{Key: "image", Value: "nginx:1.14.2"},
+							{Key: "ports", Value: []any{
+								yaml.MapSlice{
+									{Key: "containerPort", Value: uint64(80)},
+								},
+							}},
+						},
+					}},
+				},
+			},
+			Line: 1,
 		}, got[0])
 	})
+
+	t.Run("lines", func(t *testing.T) {
+		const manifest = `# leading comment
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+---
+
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm2
+`
+		r := strings.NewReader(manifest)
+		got, err := internal.LoadObjects(context.TODO(), r, internal.NewYamlMarshaler(2, true), nil, true)
+		if !assert.Nil(t, err) {
+			return
+		}
+		if !assert.Len(t, got, 2) {
+			return
+		}
+		assert.Equal(t, 2, got[0].Line)
+		assert.Equal(t, 8, got[1].Line)
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		const manifest = `resources:
+- deployment.yaml
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`
+		var skipped []int
+		got, err := internal.LoadObjectsFunc(context.TODO(), strings.NewReader(manifest), internal.NewYamlMarshaler(2, true), nil, true, func(i int, err error) error {
+			assert.ErrorIs(t, err, internal.ErrLoadObject)
+			skipped = append(skipped, i)
+			return nil
+		})
+		if !assert.Nil(t, err) || !assert.Len(t, got, 1) {
+			return
+		}
+		assert.Equal(t, "cm", got[0].Header.Metadata.Name)
+		assert.Equal(t, []int{0}, skipped)
+
+		_, err = internal.LoadObjects(context.TODO(), strings.NewReader(manifest), internal.NewYamlMarshaler(2, true), nil, true)
+		assert.ErrorIs(t, err, internal.ErrLoadObject)
+	})
 }
 
 func TestLoadObjectFromMap(t *testing.T) {
 	marshaler := &mockLoadObjectFromMapkMarshaler{}
 
+	t.Run("labels before normalization", func(t *testing.T) {
+		rules, err := internal.ParseIgnoreRules([]string{"metadata.labels"})
+		if !assert.Nil(t, err) {
+			return
+		}
+		got, err := internal.LoadObjectFromMap(context.TODO(), marshaler, rules, map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": yaml.MapSlice{
+				{Key: "name", Value: "test"},
+				{Key: "labels", Value: yaml.MapSlice{
+					{Key: "team", Value: "api"},
+				}},
+			},
+		})
+		if !assert.Nil(t, err) {
+			return
+		}
+		assert.Equal(t, map[string]string{"team": "api"}, got.Header.Metadata.Labels)
+		assert.Equal(t, yaml.MapSlice{{Key: "name", Value: "test"}}, got.Value["metadata"])
+	})
+
 	for _, tc := range []struct {
 		title string
 		obj   map[string]any
@@ -105,6 +199,40 @@ func TestLoadObjectFromMap(t *testing.T) {
 				Body: "mocked",
 			},
 		},
+		{
+			title: "labels and annotations",
+			obj: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": yaml.MapSlice{
+					{Key: "name", Value: "test"},
+					{Key: "labels", Value: yaml.MapSlice{
+						{Key: "team", Value: "web"},
+						{Key: "version", Value: uint64(2)},
+					}},
+					{Key: "annotations", Value: map[string]any{
+						"example.com/owner": "web@example.com",
+					}},
+				},
+			},
+			want: &internal.Object{
+				Header: internal.ObjectHeader{
+					APIVersion: "v1",
+					Kind:       "ConfigMap",
+					Metadata: internal.ObjectMeta{
+						Name: "test",
+						Labels: map[string]string{
+							"team":    "web",
+							"version": "2",
+						},
+						Annotations: map[string]string{
+							"example.com/owner": "web@example.com",
+						},
+					},
+				},
+				Body: "mocked",
+			},
+		},
 		{
 			title: "no apiVersion",
 			obj: map[string]any{
@@ -148,7 +276,7 @@ func TestLoadObjectFromMap(t *testing.T) {
 		},
 	} {
 		t.Run(tc.title, func(t *testing.T) {
-			got, err := internal.LoadObjectFromMap(context.TODO(), marshaler, tc.obj)
+			got, err := internal.LoadObjectFromMap(context.TODO(), marshaler, nil, tc.obj)
 			if tc.err {
 				assert.NotNil(t, err)
 				return
@@ -156,6 +284,7 @@ func TestLoadObjectFromMap(t *testing.T) {
 			if !assert.Nil(t, err) {
 				return
 			}
+			tc.want.Value = tc.obj
 			assert.Equal(t, tc.want, got)
 		})
 	}
@@ -12,6 +12,8 @@ func NewObjectMap(sep string) *ObjectMap {
 	}
 }
 
+func (m *ObjectMap) Separator() string { return m.sep }
+
 func (m *ObjectMap) Add(obj *Object) bool {
 	id := obj.Header.IntoID(m.sep)
 	var exist bool
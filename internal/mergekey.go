@@ -0,0 +1,212 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MergeKeyRule identifies the elements of the lists pointed by the Path by the Key field.
+//
+// If the Path is a single key, the rule applies to the lists of the field at any depth,
+// otherwise to the lists at the path from the root.
+// If Kind is not empty, the rule applies only to the objects of the kind.
+type MergeKeyRule struct {
+	Kind string
+	Path Path
+	Key  string
+}
+
+var ErrInvalidMergeKeyRule = errors.New("InvalidMergeKeyRule")
+
+// ParseMergeKeyRule parses a rule in the form of [KIND:]PATH=KEY.
+//
+//	containers=name
+//	Service:spec.ports=port
+//	MyResource:spec.items[*].targets=id
+func ParseMergeKeyRule(s string) (*MergeKeyRule, error) {
+	i := strings.LastIndex(s, "=")
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %s: key is missing", ErrInvalidMergeKeyRule, s)
+	}
+	key := strings.TrimSpace(s[i+1:])
+	if key == "" {
+		return nil, fmt.Errorf("%w: %s: key is empty", ErrInvalidMergeKeyRule, s)
+	}
+	r, err := ParseIgnoreRule(s[:i])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidMergeKeyRule, s, err)
+	}
+	return &MergeKeyRule{
+		Kind: r.Kind,
+		Path: r.Path,
+		Key:  key,
+	}, nil
+}
+
+func (r *MergeKeyRule) String() string {
+	x := r.Path.String() + "=" + r.Key
+	if r.Kind == "" {
+		return x
+	}
+	return r.Kind + ":" + x
+}
+
+func (r *MergeKeyRule) isField() bool {
+	return len(r.Path) == 1 && r.Path[0].Type == PathKey
+}
+
+func (r *MergeKeyRule) match(kind string, path Path) bool {
+	if r.Kind != "" && r.Kind != kind {
+		return false
+	}
+	if r.isField() {
+		if len(path) == 0 {
+			return false
+		}
+		last := path[len(path)-1]
+		return last.Type == PathKey && last.Key == r.Path[0].Key
+	}
+	return r.Path.Match(path)
+}
+
+// MergeKeyRules finds the merge key of the lists.
+// The first matched rule wins.
+type MergeKeyRules []*MergeKeyRule
+
+func ParseMergeKeyRules(xs []string) (MergeKeyRules, error) {
+	rules := make(MergeKeyRules, len(xs))
+	for i, x := range xs {
+		r, err := ParseMergeKeyRule(x)
+		if err != nil {
+			return nil, err
+		}
+		rules[i] = r
+	}
+	return rules, nil
+}
+
+// Lookup returns the merge key of the list at the path of the object of the kind.
+func (rs MergeKeyRules) Lookup(kind string, path Path) (string, bool) {
+	for _, r := range rs {
+		if r.match(kind, path) {
+			return r.Key, true
+		}
+	}
+	return "", false
+}
+
+// DefaultMergeKeyRules are the strategic merge patch keys of the built-in kinds.
+var DefaultMergeKeyRules = func() MergeKeyRules {
+	rules, err := ParseMergeKeyRules([]string{
+		// ServiceSpec
+		"Service:spec.ports=port",
+		// PodSpec
+		"containers=name",
+		"initContainers=name",
+		"ephemeralContainers=name",
+		"volumes=name",
+		"imagePullSecrets=name",
+		"hostAliases=ip",
+		"topologySpreadConstraints=topologyKey",
+		"resourceClaims=name",
+		"schedulingGates=name",
+		// Container
+		"env=name",
+		"ports=containerPort",
+		"volumeMounts=mountPath",
+		"volumeDevices=devicePath",
+		"resizePolicy=resourceName",
+		// ObjectMeta
+		"ownerReferences=uid",
+		// status
+		"conditions=type",
+	})
+	if err != nil {
+		panic(err)
+	}
+	return rules
+}()
+
+// mergeKeyOf returns the merge key of the lists if all the elements have distinct values of the key.
+func (rs MergeKeyRules) mergeKeyOf(kind string, path Path, lists ...[]any) (string, bool) {
+	key, ok := rs.Lookup(kind, path)
+	if !ok {
+		return "", false
+	}
+	for _, xs := range lists {
+		seen := map[string]bool{}
+		for _, x := range xs {
+			v, ok := treeGet(x, key)
+			if !ok {
+				return "", false
+			}
+			s := fmt.Sprint(v)
+			if seen[s] {
+				return "", false
+			}
+			seen[s] = true
+		}
+	}
+	return key, true
+}
+
+var _ Normalizer = &MergeKeyAligner{}
+
+// MergeKeyAligner sorts the elements of the keyed lists by the merge key,
+// so that the elements with the same key are aligned in the text diff.
+type MergeKeyAligner struct {
+	rules MergeKeyRules
+}
+
+func NewMergeKeyAligner(rules MergeKeyRules) *MergeKeyAligner {
+	return &MergeKeyAligner{
+		rules: rules,
+	}
+}
+
+func (a *MergeKeyAligner) Normalize(_ context.Context, obj map[string]any) error {
+	kind, _ := obj["kind"].(string)
+	_ = a.align(kind, nil, obj)
+	return nil
+}
+
+func (a *MergeKeyAligner) align(kind string, path Path, v any) any {
+	if xs, ok := v.([]any); ok {
+		for i, x := range xs {
+			xs[i] = a.align(kind, path.Append(&PathElement{Type: PathIndex, Index: i}), x)
+		}
+		if key, ok := a.rules.mergeKeyOf(kind, path, xs); ok {
+			sort.SliceStable(xs, func(i, j int) bool {
+				x, _ := treeGet(xs[i], key)
+				y, _ := treeGet(xs[j], key)
+				return mergeKeyLess(x, y)
+			})
+		}
+		return xs
+	}
+
+	items, ok := treeItems(v)
+	if !ok {
+		return v
+	}
+	for _, x := range items {
+		k := treeKeyString(x.Key)
+		v = treeSet(v, k, a.align(kind, path.Append(&PathElement{Type: PathKey, Key: k}), x.Value))
+	}
+	return v
+}
+
+// mergeKeyLess compares the keys numerically if both are numbers, otherwise as strings.
+func mergeKeyLess(x, y any) bool {
+	a, b := fmt.Sprint(x), fmt.Sprint(y)
+	if p, err := strconv.ParseFloat(a, 64); err == nil {
+		if q, err := strconv.ParseFloat(b, 64); err == nil {
+			return p < q
+		}
+	}
+	return a < b
+}
@@ -0,0 +1,177 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/berquerant/k8s-object-diff-go/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMergeKeyRule(t *testing.T) {
+	for _, tc := range []struct {
+		title string
+		rule  string
+		want  string
+		err   bool
+	}{
+		{
+			title: "field",
+			rule:  "containers=name",
+			want:  "containers=name",
+		},
+		{
+			title: "kind and path",
+			rule:  "Service:spec.ports=port",
+			want:  "Service:spec.ports=port",
+		},
+		{
+			title: "match in path",
+			rule:  "spec.items[name=a].targets=id",
+			want:  "spec.items[name=a].targets=id",
+		},
+		{
+			title: "no key",
+			rule:  "containers",
+			err:   true,
+		},
+		{
+			title: "empty key",
+			rule:  "containers=",
+			err:   true,
+		},
+		{
+			title: "no path",
+			rule:  "=name",
+			err:   true,
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			got, err := internal.ParseMergeKeyRule(tc.rule)
+			if tc.err {
+				assert.ErrorIs(t, err, internal.ErrInvalidMergeKeyRule)
+				return
+			}
+			if !assert.Nil(t, err) {
+				return
+			}
+			assert.Equal(t, tc.want, got.String())
+		})
+	}
+}
+
+func TestMergeKeyRules(t *testing.T) {
+	user, err := internal.ParseMergeKeyRules([]string{
+		"MyResource:spec.targets=id",
+		"spec.items[*].values=value",
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	rules := append(user, internal.DefaultMergeKeyRules...)
+
+	for _, tc := range []struct {
+		title string
+		kind  string
+		path  string
+		want  string
+		ok    bool
+	}{
+		{
+			title: "containers",
+			kind:  "Deployment",
+			path:  "spec.template.spec.containers",
+			want:  "name",
+			ok:    true,
+		},
+		{
+			title: "container ports",
+			kind:  "Pod",
+			path:  "spec.containers[name=app].ports",
+			want:  "containerPort",
+			ok:    true,
+		},
+		{
+			title: "service ports",
+			kind:  "Service",
+			path:  "spec.ports",
+			want:  "port",
+			ok:    true,
+		},
+		{
+			title: "user kind",
+			kind:  "MyResource",
+			path:  "spec.targets",
+			want:  "id",
+			ok:    true,
+		},
+		{
+			title: "user kind mismatch",
+			kind:  "OtherResource",
+			path:  "spec.targets",
+		},
+		{
+			title: "user wildcard",
+			kind:  "OtherResource",
+			path:  "spec.items[0].values",
+			want:  "value",
+			ok:    true,
+		},
+		{
+			title: "not keyed",
+			kind:  "Pod",
+			path:  "spec.tolerations",
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			path, err := internal.ParsePath(tc.path)
+			if !assert.Nil(t, err) {
+				return
+			}
+			got, ok := rules.Lookup(tc.kind, path)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestMergeKeyAligner(t *testing.T) {
+	const manifest = `apiVersion: v1
+kind: Pod
+metadata:
+  name: app
+spec:
+  containers:
+  - name: b
+    ports:
+    - containerPort: 9090
+    - containerPort: 80
+    - containerPort: 8080
+  - name: a
+    args:
+    - z
+    - x
+  tolerations:
+  - key: b
+  - key: a
+`
+	const want = `apiVersion: v1
+kind: Pod
+metadata:
+  name: app
+spec:
+  containers:
+  - name: a
+    args:
+    - z
+    - x
+  - name: b
+    ports:
+    - containerPort: 80
+    - containerPort: 8080
+    - containerPort: 9090
+  tolerations:
+  - key: b
+  - key: a
+`
+	assert.Equal(t, want, normalizeManifest(t, internal.NewMergeKeyAligner(internal.DefaultMergeKeyRules), manifest))
+}
@@ -0,0 +1,185 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// MergePatch returns the RFC 7386 JSON merge patch that turns left into right.
+// The removed keys are null and lists are replaced.
+// A nil side means an absent object, then the patch is the whole right object or null.
+// Returns false if left and right are the same.
+func MergePatch(left, right any) (any, bool) {
+	p := &mergePatcher{}
+	return p.patchObject(left, right)
+}
+
+// StrategicMergePatch returns the Kubernetes strategic merge patch that turns left into right.
+// It is MergePatch except the lists that have merge keys from the rules.
+// The elements of such lists are patched by the keys,
+// and the removed elements are the `$patch: delete` directives.
+// The order of the elements is not patched.
+func StrategicMergePatch(left, right any, rules MergeKeyRules) (any, bool) {
+	p := &mergePatcher{
+		rules:     rules,
+		strategic: true,
+	}
+	for _, x := range []any{left, right} {
+		if v, ok := treeGet(x, "kind"); ok {
+			p.kind, _ = v.(string)
+			break
+		}
+	}
+	return p.patchObject(left, right)
+}
+
+type mergePatcher struct {
+	rules     MergeKeyRules
+	kind      string
+	strategic bool
+}
+
+func (p *mergePatcher) patchObject(left, right any) (any, bool) {
+	switch {
+	case left == nil && right == nil:
+		return nil, false
+	case left == nil:
+		return JSONValue(right), true
+	case right == nil:
+		return nil, true
+	default:
+		return p.patch(nil, left, right)
+	}
+}
+
+func (p *mergePatcher) patch(path Path, left, right any) (any, bool) {
+	leftItems, leftIsMap := treeItems(left)
+	rightItems, rightIsMap := treeItems(right)
+	if leftIsMap && rightIsMap {
+		r := map[string]any{}
+		for _, x := range leftItems {
+			k := treeKeyString(x.Key)
+			y, ok := treeGet(right, k)
+			if !ok {
+				r[k] = nil
+				continue
+			}
+			if v, changed := p.patch(path.Append(&PathElement{Type: PathKey, Key: k}), x.Value, y); changed {
+				r[k] = v
+			}
+		}
+		for _, x := range rightItems {
+			k := treeKeyString(x.Key)
+			if _, ok := treeGet(left, k); !ok {
+				r[k] = JSONValue(x.Value)
+			}
+		}
+		return r, len(r) > 0
+	}
+
+	leftList, leftIsList := left.([]any)
+	rightList, rightIsList := right.([]any)
+	if p.strategic && leftIsList && rightIsList {
+		if key, ok := p.rules.mergeKeyOf(p.kind, path, leftList, rightList); ok {
+			return p.patchKeyedList(path, key, leftList, rightList)
+		}
+	}
+
+	if reflect.DeepEqual(left, right) {
+		return nil, false
+	}
+	return JSONValue(right), true
+}
+
+func (p *mergePatcher) patchKeyedList(path Path, key string, left, right []any) (any, bool) {
+	var (
+		elementKey = func(x any) (any, string) {
+			v, _ := treeGet(x, key)
+			return v, fmt.Sprint(v)
+		}
+		leftIndex  = map[string]any{}
+		rightIndex = map[string]bool{}
+		r          = []any{}
+	)
+	for _, x := range left {
+		_, k := elementKey(x)
+		leftIndex[k] = x
+	}
+	for _, x := range right {
+		v, k := elementKey(x)
+		rightIndex[k] = true
+		y, ok := leftIndex[k]
+		if !ok {
+			r = append(r, JSONValue(x))
+			continue
+		}
+		e, changed := p.patch(path.Append(&PathElement{Type: PathMatch, Key: key, Value: k}), y, x)
+		if !changed {
+			continue
+		}
+		if m, ok := e.(map[string]any); ok {
+			m[key] = JSONValue(v)
+		}
+		r = append(r, e)
+	}
+	for _, x := range left {
+		v, k := elementKey(x)
+		if !rightIndex[k] {
+			r = append(r, map[string]any{
+				key:      JSONValue(v),
+				"$patch": "delete",
+			})
+		}
+	}
+	return r, len(r) > 0
+}
+
+var _ ObjectDiffer = &MergePatchObjectDiffer{}
+
+// MergePatchObjectDiffer reports the merge patch that turns the left object into the right object as Diff.
+type MergePatchObjectDiffer struct {
+	rules     MergeKeyRules
+	strategic bool
+}
+
+// NewMergePatchObjectDiffer returns a new [MergePatchObjectDiffer] for RFC 7386 JSON merge patches.
+func NewMergePatchObjectDiffer() *MergePatchObjectDiffer {
+	return &MergePatchObjectDiffer{}
+}
+
+// NewStrategicMergePatchObjectDiffer returns a new [MergePatchObjectDiffer] for strategic merge patches.
+func NewStrategicMergePatchObjectDiffer(rules MergeKeyRules) *MergePatchObjectDiffer {
+	return &MergePatchObjectDiffer{
+		rules:     rules,
+		strategic: true,
+	}
+}
+
+func (d *MergePatchObjectDiffer) ObjectDiff(_ context.Context, pair *ObjectPair) (*ObjectDiff, error) {
+	var left, right any
+	if x := pair.Left; x != nil {
+		left = x.Value
+	}
+	if x := pair.Right; x != nil {
+		right = x.Value
+	}
+
+	var (
+		patch   any
+		changed bool
+	)
+	if d.strategic {
+		patch, changed = StrategicMergePatch(left, right, d.rules)
+	} else {
+		patch, changed = MergePatch(left, right)
+	}
+	if !changed {
+		return newObjectDiff(pair, ""), nil
+	}
+	b, err := marshalJSON(patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merge patch: id=%s: %w", pair, err)
+	}
+	return newObjectDiff(pair, string(b)), nil
+}
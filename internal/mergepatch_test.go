@@ -0,0 +1,106 @@
+package internal_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/berquerant/k8s-object-diff-go/internal"
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergePatch(t *testing.T) {
+	var (
+		left = map[string]any{
+			"kind": "Pod",
+			"metadata": yaml.MapSlice{
+				{Key: "name", Value: "app"},
+				{Key: "labels", Value: yaml.MapSlice{{Key: "a", Value: "1"}, {Key: "b", Value: "2"}}},
+			},
+			"spec": yaml.MapSlice{
+				{Key: "containers", Value: []any{
+					yaml.MapSlice{{Key: "name", Value: "app"}, {Key: "image", Value: "app:1"}},
+					yaml.MapSlice{{Key: "name", Value: "sidecar"}, {Key: "image", Value: "sidecar:1"}},
+				}},
+			},
+		}
+		right = map[string]any{
+			"kind": "Pod",
+			"metadata": yaml.MapSlice{
+				{Key: "name", Value: "app"},
+				{Key: "labels", Value: yaml.MapSlice{{Key: "a", Value: "1"}, {Key: "c", Value: "3"}}},
+			},
+			"spec": yaml.MapSlice{
+				{Key: "containers", Value: []any{
+					yaml.MapSlice{{Key: "name", Value: "init"}, {Key: "image", Value: "init:1"}},
+					yaml.MapSlice{{Key: "name", Value: "app"}, {Key: "image", Value: "app:2"}},
+				}},
+			},
+		}
+		marshal = func(t *testing.T, v any) string {
+			b, err := json.Marshal(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return string(b)
+		}
+	)
+
+	t.Run("merge", func(t *testing.T) {
+		for _, tc := range []struct {
+			title   string
+			left    any
+			right   any
+			want    string
+			changed bool
+		}{
+			{
+				title: "same",
+				left:  left,
+				right: left,
+				want:  "{}",
+			},
+			{
+				title:   "add",
+				right:   map[string]any{"kind": "Pod"},
+				want:    `{"kind":"Pod"}`,
+				changed: true,
+			},
+			{
+				title:   "destroy",
+				left:    left,
+				want:    "null",
+				changed: true,
+			},
+			{
+				title:   "change",
+				left:    left,
+				right:   right,
+				want:    `{"metadata":{"labels":{"b":null,"c":"3"}},"spec":{"containers":[{"image":"init:1","name":"init"},{"image":"app:2","name":"app"}]}}`,
+				changed: true,
+			},
+		} {
+			t.Run(tc.title, func(t *testing.T) {
+				got, changed := internal.MergePatch(tc.left, tc.right)
+				assert.Equal(t, tc.changed, changed)
+				assert.Equal(t, tc.want, marshal(t, got))
+			})
+		}
+	})
+
+	t.Run("strategic", func(t *testing.T) {
+		got, changed := internal.StrategicMergePatch(left, right, internal.DefaultMergeKeyRules)
+		assert.True(t, changed)
+		assert.Equal(t,
+			`{"metadata":{"labels":{"b":null,"c":"3"}},"spec":{"containers":[{"image":"init:1","name":"init"},{"image":"app:2","name":"app"},{"$patch":"delete","name":"sidecar"}]}}`,
+			marshal(t, got),
+		)
+	})
+
+	t.Run("strategic without keys", func(t *testing.T) {
+		got, changed := internal.StrategicMergePatch(left, right, nil)
+		assert.True(t, changed)
+		want, _ := internal.MergePatch(left, right)
+		assert.Equal(t, marshal(t, want), marshal(t, got))
+	})
+}
@@ -0,0 +1,22 @@
+package internal
+
+import "context"
+
+// Normalizer rewrites a decoded object before it is marshaled.
+type Normalizer interface {
+	Normalize(ctx context.Context, obj map[string]any) error
+}
+
+var _ Normalizer = Normalizers(nil)
+
+// Normalizers applies the normalizers in order.
+type Normalizers []Normalizer
+
+func (ns Normalizers) Normalize(ctx context.Context, obj map[string]any) error {
+	for _, n := range ns {
+		if err := n.Normalize(ctx, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
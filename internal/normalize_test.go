@@ -0,0 +1,42 @@
+package internal_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/berquerant/k8s-object-diff-go/internal"
+)
+
+// normalizeManifests normalizes the documents of the manifest by n and marshals them.
+func normalizeManifests(t *testing.T, n internal.Normalizer, manifest string) []string {
+	t.Helper()
+	ctx := context.TODO()
+	xs, err := internal.NewYamlUnmarshaler(strings.NewReader(manifest), map[string]any{}, true).Unmarshal(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	marshaler := internal.NewYamlMarshaler(2, true)
+	result := make([]string, len(xs))
+	for i, x := range xs {
+		if err := n.Normalize(ctx, x); err != nil {
+			t.Fatal(err)
+		}
+		b, err := marshaler.Marshal(ctx, x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result[i] = string(b)
+	}
+	return result
+}
+
+// normalizeManifest normalizes the manifest of a document by n and marshals it.
+func normalizeManifest(t *testing.T, n internal.Normalizer, manifest string) string {
+	t.Helper()
+	xs := normalizeManifests(t, n, manifest)
+	if len(xs) != 1 {
+		t.Fatalf("want 1 document, got %d", len(xs))
+	}
+	return xs[0]
+}
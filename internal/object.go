@@ -12,8 +12,10 @@ type ObjectHeader struct {
 }
 
 type ObjectMeta struct {
-	Namespace string `yaml:"namespace"`
-	Name      string `yaml:"name"`
+	Namespace   string            `yaml:"namespace"`
+	Name        string            `yaml:"name"`
+	Labels      map[string]string `yaml:"labels"`
+	Annotations map[string]string `yaml:"annotations"`
 }
 
 func (s ObjectHeader) IntoID(sep string) string {
@@ -25,7 +27,35 @@ func (s ObjectHeader) IntoID(sep string) string {
 	}, sep)
 }
 
+// Group returns the API group, empty if the core group.
+func (s ObjectHeader) Group() string {
+	group, _, ok := strings.Cut(s.APIVersion, "/")
+	if !ok {
+		return ""
+	}
+	return group
+}
+
 type Object struct {
 	Header ObjectHeader
 	Body   string
+	// Value is the decoded object that Body is marshaled from.
+	Value map[string]any
+	// Source is the file the object is loaded from.
+	Source string
+	// Rev is the git revision of Source, empty if Source is a local file.
+	Rev string
+	// Line is the line number of the object in Source, 0 if unknown.
+	Line int
+}
+
+// Location returns REV:Source if Rev is set, otherwise Source.
+func (o *Object) Location() string { return SourceLocation(o.Rev, o.Source) }
+
+// SourceLocation returns REV:SOURCE like git show, or SOURCE if rev is empty.
+func SourceLocation(rev, source string) string {
+	if rev == "" {
+		return source
+	}
+	return rev + ":" + source
 }
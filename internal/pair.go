@@ -4,9 +4,27 @@ import "sort"
 
 // ObjectPair is a pair of [Objects] that share the same ID.
 type ObjectPair struct {
-	ID    string
-	Left  *Object
-	Right *Object
+	ID string
+	// PrevID is the ID of Left if it differs from ID, i.e. the object is renamed or moved.
+	PrevID string
+	Left   *Object
+	Right  *Object
+}
+
+// LeftID returns the ID of Left.
+func (p *ObjectPair) LeftID() string {
+	if p.PrevID != "" {
+		return p.PrevID
+	}
+	return p.ID
+}
+
+// String returns the ID, or PREV_ID => ID if renamed or moved.
+func (p *ObjectPair) String() string {
+	if p.PrevID != "" {
+		return p.PrevID + " => " + p.ID
+	}
+	return p.ID
 }
 
 func (p *ObjectPair) IsMissing() bool {
@@ -0,0 +1,262 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type PathElementType int
+
+const (
+	// PathKey is a map key, e.g. `.name` or `["helm.sh/chart"]`.
+	PathKey PathElementType = iota
+	// PathIndex is a list index, e.g. `[0]`.
+	PathIndex
+	// PathWildcard matches any map key or any list element, e.g. `*` or `[*]`.
+	PathWildcard
+	// PathMatch matches list elements by a field, e.g. `[name=app]`.
+	PathMatch
+)
+
+type PathElement struct {
+	Type  PathElementType
+	Key   string
+	Index int
+	Value string
+}
+
+func (e *PathElement) String() string {
+	switch e.Type {
+	case PathIndex:
+		return fmt.Sprintf("[%d]", e.Index)
+	case PathWildcard:
+		return "[*]"
+	case PathMatch:
+		return fmt.Sprintf("[%s=%s]", quotePathString(e.Key), quotePathString(e.Value))
+	default:
+		if isPlainPathKey(e.Key) {
+			return "." + e.Key
+		}
+		return "[" + strconv.Quote(e.Key) + "]"
+	}
+}
+
+// Path points to values in a decoded object, like a simplified JSONPath.
+//
+//	metadata.annotations["checksum/config"]
+//	spec.template.spec.containers[name=app].image
+//	spec.containers[*].env
+//	$.status
+type Path []*PathElement
+
+func (p Path) String() string {
+	var b strings.Builder
+	for i, e := range p {
+		s := e.String()
+		if i == 0 {
+			s = strings.TrimPrefix(s, ".")
+		}
+		b.WriteString(s)
+	}
+	return b.String()
+}
+
+// Append returns a new path with e appended.
+func (p Path) Append(e *PathElement) Path {
+	r := make(Path, len(p), len(p)+1)
+	copy(r, p)
+	return append(r, e)
+}
+
+// Match reports whether the path matches p.
+// Wildcards in p match any element.
+func (p Path) Match(path Path) bool {
+	if len(p) != len(path) {
+		return false
+	}
+	for i, e := range p {
+		if e.Type == PathWildcard {
+			continue
+		}
+		if *e != *path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+var plainPathKeyRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func isPlainPathKey(s string) bool { return plainPathKeyRegexp.MatchString(s) }
+
+func quotePathString(s string) string {
+	if isPlainPathKey(s) {
+		return s
+	}
+	return strconv.Quote(s)
+}
+
+var ErrInvalidPath = errors.New("InvalidPath")
+
+func ParsePath(s string) (Path, error) {
+	p := &pathParser{
+		s: s,
+	}
+	r, err := p.parse()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", ErrInvalidPath, s, err)
+	}
+	return r, nil
+}
+
+type pathParser struct {
+	s   string
+	pos int
+}
+
+func (p *pathParser) eof() bool  { return p.pos >= len(p.s) }
+func (p *pathParser) peek() byte { return p.s[p.pos] }
+
+func (p *pathParser) parse() (Path, error) {
+	if strings.HasPrefix(p.s, "$") {
+		p.pos++
+	}
+	var r Path
+	for !p.eof() {
+		switch p.peek() {
+		case '[':
+			e, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			r = append(r, e)
+		case '.':
+			p.pos++
+			if len(r) == 0 && !p.eof() && p.peek() == '[' {
+				continue // allow `.["key"]` and `$.[0]`
+			}
+			fallthrough
+		default:
+			e, err := p.parseKey()
+			if err != nil {
+				return nil, err
+			}
+			r = append(r, e)
+		}
+	}
+	if len(r) == 0 {
+		return nil, errors.New("empty path")
+	}
+	return r, nil
+}
+
+func (p *pathParser) parseKey() (*PathElement, error) {
+	start := p.pos
+	for !p.eof() && p.peek() != '.' && p.peek() != '[' {
+		p.pos++
+	}
+	key := p.s[start:p.pos]
+	switch key {
+	case "":
+		return nil, fmt.Errorf("empty key at %d", start)
+	case "*":
+		return &PathElement{Type: PathWildcard}, nil
+	default:
+		return &PathElement{Type: PathKey, Key: key}, nil
+	}
+}
+
+func (p *pathParser) parseBracket() (*PathElement, error) {
+	start := p.pos
+	p.pos++ // [
+	if p.eof() {
+		return nil, fmt.Errorf("unclosed bracket at %d", start)
+	}
+
+	var e *PathElement
+	switch c := p.peek(); {
+	case c == '*':
+		p.pos++
+		e = &PathElement{Type: PathWildcard}
+	case c == '"' || c == '\'':
+		key, err := p.parseQuoted()
+		if err != nil {
+			return nil, err
+		}
+		e = &PathElement{Type: PathKey, Key: key}
+	default:
+		key, err := p.parseUntil("=]")
+		if err != nil {
+			return nil, err
+		}
+		if !p.eof() && p.peek() == '=' {
+			p.pos++
+			value, err := p.parseBracketValue()
+			if err != nil {
+				return nil, err
+			}
+			e = &PathElement{Type: PathMatch, Key: key, Value: value}
+			break
+		}
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 {
+			return nil, fmt.Errorf("invalid index %q at %d", key, start)
+		}
+		e = &PathElement{Type: PathIndex, Index: i}
+	}
+
+	if p.eof() || p.peek() != ']' {
+		return nil, fmt.Errorf("unclosed bracket at %d", start)
+	}
+	p.pos++
+	return e, nil
+}
+
+func (p *pathParser) parseBracketValue() (string, error) {
+	if !p.eof() && (p.peek() == '"' || p.peek() == '\'') {
+		return p.parseQuoted()
+	}
+	return p.parseUntil("]")
+}
+
+func (p *pathParser) parseUntil(stops string) (string, error) {
+	start := p.pos
+	for !p.eof() && !strings.ContainsRune(stops, rune(p.peek())) {
+		p.pos++
+	}
+	if p.eof() {
+		return "", fmt.Errorf("unclosed bracket at %d", start)
+	}
+	s := strings.TrimSpace(p.s[start:p.pos])
+	if s == "" {
+		return "", fmt.Errorf("empty bracket at %d", start)
+	}
+	return s, nil
+}
+
+func (p *pathParser) parseQuoted() (string, error) {
+	start := p.pos
+	quote := p.peek()
+	p.pos++
+	var b strings.Builder
+	for !p.eof() {
+		c := p.peek()
+		p.pos++
+		switch c {
+		case quote:
+			return b.String(), nil
+		case '\\':
+			if p.eof() {
+				return "", fmt.Errorf("unclosed quote at %d", start)
+			}
+			b.WriteByte(p.peek())
+			p.pos++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", fmt.Errorf("unclosed quote at %d", start)
+}
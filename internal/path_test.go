@@ -0,0 +1,160 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/berquerant/k8s-object-diff-go/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePath(t *testing.T) {
+	for _, tc := range []struct {
+		title string
+		path  string
+		want  internal.Path
+		str   string
+		err   bool
+	}{
+		{
+			title: "empty",
+			path:  "",
+			err:   true,
+		},
+		{
+			title: "root only",
+			path:  "$",
+			err:   true,
+		},
+		{
+			title: "key",
+			path:  "status",
+			want: internal.Path{
+				{Type: internal.PathKey, Key: "status"},
+			},
+			str: "status",
+		},
+		{
+			title: "jsonpath",
+			path:  "$.metadata.name",
+			want: internal.Path{
+				{Type: internal.PathKey, Key: "metadata"},
+				{Type: internal.PathKey, Key: "name"},
+			},
+			str: "metadata.name",
+		},
+		{
+			title: "leading dot",
+			path:  ".metadata.name",
+			want: internal.Path{
+				{Type: internal.PathKey, Key: "metadata"},
+				{Type: internal.PathKey, Key: "name"},
+			},
+			str: "metadata.name",
+		},
+		{
+			title: "quoted key",
+			path:  `metadata.annotations["checksum/config"]`,
+			want: internal.Path{
+				{Type: internal.PathKey, Key: "metadata"},
+				{Type: internal.PathKey, Key: "annotations"},
+				{Type: internal.PathKey, Key: "checksum/config"},
+			},
+			str: `metadata.annotations["checksum/config"]`,
+		},
+		{
+			title: "single quoted key",
+			path:  `metadata.labels['helm.sh/chart']`,
+			want: internal.Path{
+				{Type: internal.PathKey, Key: "metadata"},
+				{Type: internal.PathKey, Key: "labels"},
+				{Type: internal.PathKey, Key: "helm.sh/chart"},
+			},
+			str: `metadata.labels["helm.sh/chart"]`,
+		},
+		{
+			title: "escaped quote",
+			path:  `a["b\"c"]`,
+			want: internal.Path{
+				{Type: internal.PathKey, Key: "a"},
+				{Type: internal.PathKey, Key: `b"c`},
+			},
+			str: `a["b\"c"]`,
+		},
+		{
+			title: "index",
+			path:  "spec.containers[0].image",
+			want: internal.Path{
+				{Type: internal.PathKey, Key: "spec"},
+				{Type: internal.PathKey, Key: "containers"},
+				{Type: internal.PathIndex, Index: 0},
+				{Type: internal.PathKey, Key: "image"},
+			},
+			str: "spec.containers[0].image",
+		},
+		{
+			title: "wildcard",
+			path:  "spec.containers[*].env.*",
+			want: internal.Path{
+				{Type: internal.PathKey, Key: "spec"},
+				{Type: internal.PathKey, Key: "containers"},
+				{Type: internal.PathWildcard},
+				{Type: internal.PathKey, Key: "env"},
+				{Type: internal.PathWildcard},
+			},
+			str: "spec.containers[*].env[*]",
+		},
+		{
+			title: "match",
+			path:  "spec.containers[name=app].image",
+			want: internal.Path{
+				{Type: internal.PathKey, Key: "spec"},
+				{Type: internal.PathKey, Key: "containers"},
+				{Type: internal.PathMatch, Key: "name", Value: "app"},
+				{Type: internal.PathKey, Key: "image"},
+			},
+			str: "spec.containers[name=app].image",
+		},
+		{
+			title: "match quoted",
+			path:  `volumeMounts[mountPath="/etc/app"]`,
+			want: internal.Path{
+				{Type: internal.PathKey, Key: "volumeMounts"},
+				{Type: internal.PathMatch, Key: "mountPath", Value: "/etc/app"},
+			},
+			str: `volumeMounts[mountPath="/etc/app"]`,
+		},
+		{
+			title: "unclosed bracket",
+			path:  "a[0",
+			err:   true,
+		},
+		{
+			title: "unclosed quote",
+			path:  `a["b]`,
+			err:   true,
+		},
+		{
+			title: "invalid index",
+			path:  "a[-1]",
+			err:   true,
+		},
+		{
+			title: "empty key",
+			path:  "a..b",
+			err:   true,
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			got, err := internal.ParsePath(tc.path)
+			if tc.err {
+				assert.ErrorIs(t, err, internal.ErrInvalidPath)
+				return
+			}
+			if !assert.Nil(t, err) {
+				return
+			}
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.str, got.String())
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// NormalizerFactory builds a [Normalizer] of a profile.
+type NormalizerFactory func() (Normalizer, error)
+
+var (
+	ErrUnknownNormalizerProfile = errors.New("UnknownNormalizerProfile")
+
+	normalizerProfiles = map[string]NormalizerFactory{
+		"server": NewServerFieldsNormalizer,
+		"defaults": func() (Normalizer, error) {
+			return NewDefaultsFiller(), nil
+		},
+		"kustomize": func() (Normalizer, error) {
+			return NewHashSuffixStripper(), nil
+		},
+	}
+)
+
+// RegisterNormalizerProfile makes the normalizer available by the name.
+// It overwrites the profile of the same name.
+func RegisterNormalizerProfile(name string, factory NormalizerFactory) {
+	normalizerProfiles[name] = factory
+}
+
+// NormalizerProfileNames returns the names of the registered profiles in order.
+func NormalizerProfileNames() []string {
+	names := make([]string, 0, len(normalizerProfiles))
+	for k := range normalizerProfiles {
+		names = append(names, k)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// NewNormalizerProfile returns the normalizer of the profile.
+func NewNormalizerProfile(name string) (Normalizer, error) {
+	f, ok := normalizerProfiles[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownNormalizerProfile, name)
+	}
+	n, err := f()
+	if err != nil {
+		return nil, fmt.Errorf("normalizer profile %s: %w", name, err)
+	}
+	return n, nil
+}
+
+// NewServerFieldsNormalizer returns the normalizer that removes [ServerSideFields].
+func NewServerFieldsNormalizer() (Normalizer, error) {
+	return ParseIgnoreRules(ServerSideFields)
+}
@@ -0,0 +1,79 @@
+package internal_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/berquerant/k8s-object-diff-go/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizerProfile(t *testing.T) {
+	t.Run("unknown", func(t *testing.T) {
+		_, err := internal.NewNormalizerProfile("unknown")
+		assert.ErrorIs(t, err, internal.ErrUnknownNormalizerProfile)
+	})
+
+	t.Run("register", func(t *testing.T) {
+		internal.RegisterNormalizerProfile("test-labels", func() (internal.Normalizer, error) {
+			return internal.ParseIgnoreRules([]string{"metadata.labels"})
+		})
+		assert.Contains(t, internal.NormalizerProfileNames(), "test-labels")
+		n, err := internal.NewNormalizerProfile("test-labels")
+		if !assert.Nil(t, err) {
+			return
+		}
+		obj := map[string]any{
+			"metadata": map[string]any{
+				"name":   "app",
+				"labels": map[string]any{"app": "app"},
+			},
+		}
+		assert.Nil(t, n.Normalize(context.TODO(), obj))
+		assert.Equal(t, map[string]any{
+			"metadata": map[string]any{
+				"name": "app",
+			},
+		}, obj)
+	})
+
+	t.Run("server", func(t *testing.T) {
+		const manifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    kubectl.kubernetes.io/last-applied-configuration: |
+      {}
+  creationTimestamp: "2024-01-01T00:00:00Z"
+  generation: 3
+  managedFields:
+  - manager: kubectl
+  name: app
+  resourceVersion: "12345"
+  uid: 0b1c2d3e-4f50-6172-8394-a5b6c7d8e9f0
+spec:
+  template:
+    metadata:
+      creationTimestamp: null
+      labels:
+        app: app
+status:
+  replicas: 2
+`
+		const want = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    metadata:
+      labels:
+        app: app
+`
+		n, err := internal.NewNormalizerProfile("server")
+		if !assert.Nil(t, err) {
+			return
+		}
+		assert.Equal(t, want, normalizeManifest(t, n, manifest))
+	})
+}
@@ -0,0 +1,141 @@
+package internal
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RedactRule replaces the values pointed by the Path with the hash markers.
+// If Kind is not empty, the rule applies only to the objects of the kind.
+type RedactRule struct {
+	Kind string
+	Path Path
+}
+
+var ErrInvalidRedactRule = errors.New("InvalidRedactRule")
+
+// ParseRedactRule parses a rule in the form of [KIND:]PATH like [ParseIgnoreRule].
+func ParseRedactRule(s string) (*RedactRule, error) {
+	r, err := ParseIgnoreRule(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRedactRule, err)
+	}
+	return &RedactRule{
+		Kind: r.Kind,
+		Path: r.Path,
+	}, nil
+}
+
+func (r *RedactRule) String() string {
+	if r.Kind == "" {
+		return r.Path.String()
+	}
+	return r.Kind + ":" + r.Path.String()
+}
+
+func (r *RedactRule) apply(obj map[string]any, redact func(any) any) {
+	if kind, _ := obj["kind"].(string); r.Kind != "" && kind != r.Kind {
+		return
+	}
+	if len(r.Path) > 0 {
+		_ = treeUpdate(obj, r.Path, redact)
+	}
+}
+
+// DefaultRedactRules are the fields of the Secrets that hold the values,
+// including the copy in the annotation by kubectl apply.
+var DefaultRedactRules = []string{
+	"Secret:data",
+	"Secret:stringData",
+	`Secret:metadata.annotations["kubectl.kubernetes.io/last-applied-configuration"]`,
+}
+
+// RedactRules are the fields to be redacted.
+type RedactRules []*RedactRule
+
+func ParseRedactRules(xs []string) (RedactRules, error) {
+	rules := make(RedactRules, len(xs))
+	for i, x := range xs {
+		r, err := ParseRedactRule(x)
+		if err != nil {
+			return nil, err
+		}
+		rules[i] = r
+	}
+	return rules, nil
+}
+
+var _ Normalizer = &Redactor{}
+
+// Redactor hides the sensitive values while keeping the changes of them visible.
+//
+// The markers are the HMAC-SHA256 of the values, so that the same values have the same markers
+// and the values cannot be guessed from the markers without the key.
+type Redactor struct {
+	rules RedactRules
+	key   []byte
+}
+
+func NewRedactor(rules RedactRules, key []byte) *Redactor {
+	return &Redactor{
+		rules: rules,
+		key:   key,
+	}
+}
+
+// NewRedactKey returns a random key, to make the markers stable only within a run.
+func NewRedactKey() ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("redact key: %w", err)
+	}
+	return key, nil
+}
+
+func (r *Redactor) Normalize(_ context.Context, obj map[string]any) error {
+	for _, x := range r.rules {
+		x.apply(obj, r.redactTree)
+	}
+	return nil
+}
+
+// redactTree redacts the scalars of the node.
+func (r *Redactor) redactTree(v any) any {
+	if xs, ok := v.([]any); ok {
+		for i, x := range xs {
+			xs[i] = r.redactTree(x)
+		}
+		return xs
+	}
+	items, ok := treeItems(v)
+	if !ok {
+		if v == nil {
+			return v
+		}
+		return r.Redact(fmt.Sprint(v))
+	}
+	for _, x := range items {
+		v = treeSet(v, treeKeyString(x.Key), r.redactTree(x.Value))
+	}
+	return v
+}
+
+const redactMarkerPrefix = "<redacted hmac-sha256:"
+
+// Redact returns the marker of the value with the prefix of the HMAC-SHA256.
+func (r *Redactor) Redact(s string) string {
+	h := hmac.New(sha256.New, r.key)
+	_, _ = h.Write([]byte(s))
+	return redactMarkerPrefix + hex.EncodeToString(h.Sum(nil))[:12] + ">"
+}
+
+// ContainsRedacted reports whether s contains the markers of [Redactor.Redact].
+func ContainsRedacted(s string) bool {
+	return strings.Contains(s, redactMarkerPrefix)
+}
@@ -0,0 +1,129 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/berquerant/k8s-object-diff-go/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactor(t *testing.T) {
+	t.Run("marker", func(t *testing.T) {
+		r := internal.NewRedactor(nil, []byte("key"))
+		assert.Equal(t, "<redacted hmac-sha256:0574b240beed>", r.Redact("s3cret"))
+		assert.Equal(t, r.Redact("s3cret"), r.Redact("s3cret"))
+		assert.NotEqual(t, r.Redact("s3cret"), r.Redact("n3w-s3cret"))
+		assert.NotEqual(t, r.Redact("s3cret"), internal.NewRedactor(nil, []byte("other")).Redact("s3cret"))
+	})
+
+	t.Run("random key", func(t *testing.T) {
+		x, err := internal.NewRedactKey()
+		if !assert.Nil(t, err) {
+			return
+		}
+		y, err := internal.NewRedactKey()
+		if !assert.Nil(t, err) {
+			return
+		}
+		assert.NotEqual(t, x, y)
+	})
+
+	for _, tc := range []struct {
+		title    string
+		rules    []string
+		manifest string
+		want     string
+	}{
+		{
+			title: "secret",
+			rules: internal.DefaultRedactRules,
+			manifest: `apiVersion: v1
+data:
+  password: czNjcmV0
+kind: Secret
+metadata:
+  name: app
+stringData:
+  config:
+    token: s3cret
+    scopes:
+    - read
+type: Opaque
+`,
+			want: `apiVersion: v1
+data:
+  password: <redacted hmac-sha256:5b4347b31c89>
+kind: Secret
+metadata:
+  name: app
+stringData:
+  config:
+    token: <redacted hmac-sha256:0574b240beed>
+    scopes:
+    - <redacted hmac-sha256:f5da4081cdf0>
+type: Opaque
+`,
+		},
+		{
+			title: "default rules do not apply to other kinds",
+			rules: internal.DefaultRedactRules,
+			manifest: `apiVersion: v1
+data:
+  password: s3cret
+kind: ConfigMap
+metadata:
+  name: app
+`,
+			want: `apiVersion: v1
+data:
+  password: s3cret
+kind: ConfigMap
+metadata:
+  name: app
+`,
+		},
+		{
+			title: "path",
+			rules: []string{"spec.containers[*].env[name=TOKEN].value"},
+			manifest: `apiVersion: v1
+kind: Pod
+metadata:
+  name: app
+spec:
+  containers:
+  - name: app
+    env:
+    - name: TOKEN
+      value: s3cret
+    - name: MODE
+      value: dev
+`,
+			want: `apiVersion: v1
+kind: Pod
+metadata:
+  name: app
+spec:
+  containers:
+  - name: app
+    env:
+    - name: TOKEN
+      value: <redacted hmac-sha256:0574b240beed>
+    - name: MODE
+      value: dev
+`,
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			rules, err := internal.ParseRedactRules(tc.rules)
+			if !assert.Nil(t, err) {
+				return
+			}
+			assert.Equal(t, tc.want, normalizeManifest(t, internal.NewRedactor(rules, []byte("key")), tc.manifest))
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := internal.ParseRedactRules([]string{"spec.["})
+		assert.ErrorIs(t, err, internal.ErrInvalidRedactRule)
+	})
+}
@@ -0,0 +1,123 @@
+package internal
+
+import (
+	"sort"
+	"strings"
+)
+
+var _ ObjectPairer = &RenamePairer{}
+
+// RenamePairer pairs the objects only in the left and the objects only in the right
+// if they are the same kind and similar, like git diff -M.
+type RenamePairer struct {
+	pairer    ObjectPairer
+	threshold float64
+}
+
+// NewRenamePairer returns a new [RenamePairer].
+// threshold is the minimum similarity in (0, 1] to pair objects.
+func NewRenamePairer(pairer ObjectPairer, threshold float64) *RenamePairer {
+	return &RenamePairer{
+		pairer:    pairer,
+		threshold: threshold,
+	}
+}
+
+type renameCandidate struct {
+	left       *ObjectPair
+	right      *ObjectPair
+	similarity float64
+}
+
+func (p *RenamePairer) ObjectPairs() []*ObjectPair {
+	var (
+		pairs       = p.pairer.ObjectPairs()
+		lefts       []*ObjectPair
+		rights      []*ObjectPair
+		candidates  []*renameCandidate
+		leftLines   = map[*ObjectPair]map[string]int{}
+		rightLines  = map[*ObjectPair]map[string]int{}
+		paired      = map[*ObjectPair]bool{}
+		renamedPair = map[*ObjectPair]*ObjectPair{}
+	)
+	for _, x := range pairs {
+		switch {
+		case x.Left != nil && x.Right == nil:
+			lefts = append(lefts, x)
+			leftLines[x] = countLines(x.Left.Body)
+		case x.Left == nil && x.Right != nil:
+			rights = append(rights, x)
+			rightLines[x] = countLines(x.Right.Body)
+		}
+	}
+	for _, l := range lefts {
+		for _, r := range rights {
+			if l.Left.Header.Kind != r.Right.Header.Kind {
+				continue
+			}
+			s := lineSimilarity(leftLines[l], rightLines[r])
+			if s < p.threshold {
+				continue
+			}
+			candidates = append(candidates, &renameCandidate{
+				left:       l,
+				right:      r,
+				similarity: s,
+			})
+		}
+	}
+	// the most similar pairs first
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].similarity > candidates[j].similarity
+	})
+	for _, c := range candidates {
+		if paired[c.left] || paired[c.right] {
+			continue
+		}
+		paired[c.left] = true
+		paired[c.right] = true
+		renamedPair[c.right] = &ObjectPair{
+			ID:     c.right.ID,
+			PrevID: c.left.ID,
+			Left:   c.left.Left,
+			Right:  c.right.Right,
+		}
+	}
+
+	result := make([]*ObjectPair, 0, len(pairs)-len(renamedPair))
+	for _, x := range pairs {
+		if r, ok := renamedPair[x]; ok {
+			result = append(result, r)
+			continue
+		}
+		if paired[x] {
+			continue
+		}
+		result = append(result, x)
+	}
+	return result
+}
+
+func countLines(s string) map[string]int {
+	r := map[string]int{}
+	for _, x := range strings.Split(strings.TrimSuffix(s, "\n"), "\n") {
+		r[x]++
+	}
+	return r
+}
+
+// lineSimilarity returns the ratio of the common lines to all the lines.
+func lineSimilarity(left, right map[string]int) float64 {
+	var common, total int
+	for k, n := range left {
+		common += min(n, right[k])
+		total += n
+	}
+	for _, n := range right {
+		total += n
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(2*common) / float64(total)
+}
@@ -0,0 +1,73 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/berquerant/k8s-object-diff-go/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenamePairer(t *testing.T) {
+	const sep = ">"
+	newObject := func(kind, namespace, name, body string) *internal.Object {
+		return &internal.Object{
+			Header: internal.ObjectHeader{
+				APIVersion: "v1",
+				Kind:       kind,
+				Metadata: internal.ObjectMeta{
+					Namespace: namespace,
+					Name:      name,
+				},
+			},
+			Body: body,
+		}
+	}
+
+	var (
+		oldCM   = newObject("ConfigMap", "default", "cm1", "name: cm1\na: 1\nb: 2\nc: 3\n")
+		newCM   = newObject("ConfigMap", "default", "cm2", "name: cm2\na: 1\nb: 2\nc: 3\n")
+		otherCM = newObject("ConfigMap", "default", "cm3", "name: cm3\nx: 1\n")
+		oldPod  = newObject("Pod", "ns1", "pod", "name: pod\nnamespace: ns1\nimage: app\n")
+		newPod  = newObject("Pod", "ns2", "pod", "name: pod\nnamespace: ns2\nimage: app\n")
+		secret  = newObject("Secret", "default", "cm1", "name: cm1\na: 1\nb: 2\nc: 3\n")
+		left    = internal.NewObjectMap(sep)
+		right   = internal.NewObjectMap(sep)
+		pairFor = func(pairs []*internal.ObjectPair, id string) *internal.ObjectPair {
+			for _, x := range pairs {
+				if x.ID == id {
+					return x
+				}
+			}
+			return nil
+		}
+	)
+	left.Add(oldCM)
+	left.Add(oldPod)
+	right.Add(newCM)
+	right.Add(otherCM)
+	right.Add(newPod)
+	right.Add(secret)
+
+	t.Run("disabled", func(t *testing.T) {
+		pairs := internal.NewRenamePairer(internal.NewObjectPairMap(left, right), 1).ObjectPairs()
+		assert.Len(t, pairs, 6)
+	})
+
+	pairs := internal.NewRenamePairer(internal.NewObjectPairMap(left, right), 0.5).ObjectPairs()
+	assert.Len(t, pairs, 4)
+
+	cm := pairFor(pairs, "v1>ConfigMap>default>cm2")
+	if assert.NotNil(t, cm) {
+		assert.Equal(t, "v1>ConfigMap>default>cm1", cm.PrevID)
+		assert.Equal(t, "v1>ConfigMap>default>cm1 => v1>ConfigMap>default>cm2", cm.String())
+		assert.Equal(t, oldCM, cm.Left)
+		assert.Equal(t, newCM, cm.Right)
+	}
+	pod := pairFor(pairs, "v1>Pod>ns2>pod")
+	if assert.NotNil(t, pod) {
+		assert.Equal(t, "v1>Pod>ns1>pod", pod.LeftID())
+	}
+	// another kind is not a rename
+	assert.Nil(t, pairFor(pairs, "v1>Secret>default>cm1").Left)
+	assert.Nil(t, pairFor(pairs, "v1>ConfigMap>default>cm3").Left)
+}
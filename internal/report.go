@@ -0,0 +1,153 @@
+package internal
+
+import "encoding/json"
+
+// ReportSchemaVersion is the version of the schema of [Report].
+// Bump it when the existing fields change incompatibly.
+const ReportSchemaVersion = 1
+
+// Report is the machine-readable result of the diff.
+type Report struct {
+	SchemaVersion int            `json:"schemaVersion"`
+	Summary       *ReportSummary `json:"summary"`
+	Entries       []*ReportEntry `json:"entries"`
+}
+
+func NewReport(entries []*ReportEntry) *Report {
+	if entries == nil {
+		entries = []*ReportEntry{}
+	}
+	summary := &ReportSummary{}
+	for _, x := range entries {
+		summary.add(x.Type)
+	}
+	return &Report{
+		SchemaVersion: ReportSchemaVersion,
+		Summary:       summary,
+		Entries:       entries,
+	}
+}
+
+// ReportSummary is the number of the objects by diff type.
+type ReportSummary struct {
+	Add     int `json:"add"`
+	Change  int `json:"change"`
+	Destroy int `json:"destroy"`
+	Rename  int `json:"rename"`
+	Move    int `json:"move"`
+}
+
+// Count counts an object of the diff type.
+func (s *ReportSummary) Count(diffType DiffType) {
+	s.add(diffType.String())
+}
+
+func (s *ReportSummary) add(diffType string) {
+	switch diffType {
+	case DiffTypeAdd.String():
+		s.Add++
+	case DiffTypeChange.String():
+		s.Change++
+	case DiffTypeDestroy.String():
+		s.Destroy++
+	case DiffTypeRename.String():
+		s.Rename++
+	case DiffTypeMove.String():
+		s.Move++
+	}
+}
+
+// ReportHeader is the parts of the object ID.
+type ReportHeader struct {
+	ID         string `json:"id"`
+	PrevID     string `json:"prevId,omitempty"`
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+	// Type is the diff type: add, change, destroy, rename or move.
+	Type string `json:"type"`
+}
+
+// NewReportHeader returns the header of the object pair.
+// The parts are from the right object, or the left object if destroyed.
+func NewReportHeader(pair *ObjectPair, diffType DiffType) ReportHeader {
+	var h ObjectHeader
+	if x := pair.Right; x != nil {
+		h = x.Header
+	} else if x := pair.Left; x != nil {
+		h = x.Header
+	}
+	return ReportHeader{
+		ID:         pair.ID,
+		PrevID:     pair.PrevID,
+		APIVersion: h.APIVersion,
+		Kind:       h.Kind,
+		Namespace:  h.Metadata.Namespace,
+		Name:       h.Metadata.Name,
+		Type:       diffType.String(),
+	}
+}
+
+// ReportEntry is the diff of an object.
+type ReportEntry struct {
+	ReportHeader
+	Diff        string `json:"diff"`
+	Left        any    `json:"left,omitempty"`
+	LeftSource  string `json:"leftSource,omitempty"`
+	LeftRev     string `json:"leftRev,omitempty"`
+	Right       any    `json:"right,omitempty"`
+	RightSource string `json:"rightSource,omitempty"`
+	RightRev    string `json:"rightRev,omitempty"`
+}
+
+func NewReportEntry(d *ObjectDiff) *ReportEntry {
+	r := &ReportEntry{
+		ReportHeader: NewReportHeader(d.Pair, d.Type),
+		Diff:         d.Diff,
+	}
+	if x := d.Pair.Left; x != nil {
+		r.Left = JSONValue(x.Value)
+		r.LeftSource = x.Source
+		r.LeftRev = x.Rev
+	}
+	if x := d.Pair.Right; x != nil {
+		r.Right = JSONValue(x.Value)
+		r.RightSource = x.Source
+		r.RightRev = x.Rev
+	}
+	return r
+}
+
+// JSONValue converts the decoded tree into the value that encoding/json can marshal.
+func JSONValue(v any) any {
+	if xs, ok := v.([]any); ok {
+		r := make([]any, len(xs))
+		for i, x := range xs {
+			r[i] = JSONValue(x)
+		}
+		return r
+	}
+	items, ok := treeItems(v)
+	if !ok {
+		return v
+	}
+	r := make(map[string]any, len(items))
+	for _, x := range items {
+		r[treeKeyString(x.Key)] = JSONValue(x.Value)
+	}
+	return r
+}
+
+// PatchEntry is the patch of an object.
+type PatchEntry struct {
+	ReportHeader
+	Patch json.RawMessage `json:"patch"`
+}
+
+func NewPatchEntry(d *ObjectDiff) *PatchEntry {
+	return &PatchEntry{
+		ReportHeader: NewReportHeader(d.Pair, d.Type),
+		Patch:        json.RawMessage(d.Diff),
+	}
+}
@@ -0,0 +1,90 @@
+package internal_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/berquerant/k8s-object-diff-go/internal"
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONValue(t *testing.T) {
+	v := map[string]any{
+		"kind": "Pod",
+		"metadata": yaml.MapSlice{
+			{Key: "name", Value: "app"},
+			{Key: "labels", Value: yaml.MapSlice{{Key: "app", Value: "app"}}},
+		},
+		"spec": yaml.MapSlice{
+			{Key: "containers", Value: []any{
+				yaml.MapSlice{{Key: "name", Value: "app"}, {Key: "port", Value: uint64(80)}},
+			}},
+		},
+	}
+	b, err := json.Marshal(internal.JSONValue(v))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, `{"kind":"Pod","metadata":{"labels":{"app":"app"},"name":"app"},"spec":{"containers":[{"name":"app","port":80}]}}`, string(b))
+}
+
+func TestNewReport(t *testing.T) {
+	newObject := func(namespace, name string) *internal.Object {
+		return &internal.Object{
+			Header: internal.ObjectHeader{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+				Metadata: internal.ObjectMeta{
+					Namespace: namespace,
+					Name:      name,
+				},
+			},
+			Value:  map[string]any{"kind": "ConfigMap"},
+			Source: "manifest.yml",
+		}
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		b, err := json.Marshal(internal.NewReport(nil))
+		if !assert.Nil(t, err) {
+			return
+		}
+		assert.Equal(t, `{"schemaVersion":1,"summary":{"add":0,"change":0,"destroy":0,"rename":0,"move":0},"entries":[]}`, string(b))
+	})
+
+	t.Run("entries", func(t *testing.T) {
+		r := internal.NewReport([]*internal.ReportEntry{
+			internal.NewReportEntry(&internal.ObjectDiff{
+				Pair: &internal.ObjectPair{
+					ID:   "v1>ConfigMap>default>destroyed",
+					Left: newObject("default", "destroyed"),
+				},
+				Diff: "diff1",
+				Type: internal.DiffTypeDestroy,
+			}),
+			internal.NewReportEntry(&internal.ObjectDiff{
+				Pair: &internal.ObjectPair{
+					ID:     "v1>ConfigMap>default>new",
+					PrevID: "v1>ConfigMap>default>old",
+					Left:   newObject("default", "old"),
+					Right:  newObject("default", "new"),
+				},
+				Diff: "diff2",
+				Type: internal.DiffTypeRename,
+			}),
+		})
+		assert.Equal(t, &internal.ReportSummary{Destroy: 1, Rename: 1}, r.Summary)
+
+		destroyed := r.Entries[0]
+		assert.Equal(t, "destroyed", destroyed.Name)
+		assert.Equal(t, "destroy", destroyed.Type)
+		assert.Nil(t, destroyed.Right)
+		assert.Equal(t, map[string]any{"kind": "ConfigMap"}, destroyed.Left)
+
+		renamed := r.Entries[1]
+		assert.Equal(t, "new", renamed.Name)
+		assert.Equal(t, "v1>ConfigMap>default>old", renamed.PrevID)
+		assert.Equal(t, "rename", renamed.Type)
+	})
+}
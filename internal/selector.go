@@ -0,0 +1,177 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// ObjectSelector matches the objects by the terms.
+// All the terms should match.
+type ObjectSelector struct {
+	terms []*objectSelectorTerm
+	raw   string
+}
+
+type objectSelectorTerm struct {
+	key   string
+	glob  string
+	regex *regexp.Regexp
+}
+
+var (
+	ErrInvalidObjectSelector = errors.New("InvalidObjectSelector")
+
+	// objectSelectorSeparatorRegexp finds the commas followed by the keys,
+	// to allow the commas in the id regex.
+	objectSelectorSeparatorRegexp = regexp.MustCompile(`,(kind|group|namespace|name|id)=`)
+)
+
+// ParseObjectSelector parses a selector in the form of KEY=VALUE[,KEY=VALUE...].
+// The keys are kind, group, namespace and name matched by glob, and id matched by regex.
+// The group of the core API is empty.
+//
+//	kind=Deployment,namespace=production
+//	group=apiextensions.k8s.io
+//	name=app-*
+//	id=^v1>ConfigMap>
+func ParseObjectSelector(s string) (*ObjectSelector, error) {
+	var (
+		terms []*objectSelectorTerm
+		rest  = s
+	)
+	for rest != "" {
+		term := rest
+		rest = ""
+		if loc := objectSelectorSeparatorRegexp.FindStringIndex(term); loc != nil {
+			term, rest = term[:loc[0]], term[loc[0]+1:]
+		}
+		key, value, ok := strings.Cut(term, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: %s: missing =", ErrInvalidObjectSelector, s)
+		}
+		t := &objectSelectorTerm{key: key}
+		switch key {
+		case "kind", "group", "namespace", "name":
+			if _, err := path.Match(value, ""); err != nil {
+				return nil, fmt.Errorf("%w: %s: %w", ErrInvalidObjectSelector, s, err)
+			}
+			t.glob = value
+		case "id":
+			r, err := regexp.Compile(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: %w", ErrInvalidObjectSelector, s, err)
+			}
+			t.regex = r
+		default:
+			return nil, fmt.Errorf("%w: %s: unknown key %s", ErrInvalidObjectSelector, s, key)
+		}
+		terms = append(terms, t)
+	}
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("%w: empty", ErrInvalidObjectSelector)
+	}
+	return &ObjectSelector{
+		terms: terms,
+		raw:   s,
+	}, nil
+}
+
+func (s *ObjectSelector) String() string { return s.raw }
+
+// Match reports whether the object with the id matches the selector.
+func (s *ObjectSelector) Match(header ObjectHeader, id string) bool {
+	for _, t := range s.terms {
+		if !t.match(header, id) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *objectSelectorTerm) match(header ObjectHeader, id string) bool {
+	var target string
+	switch t.key {
+	case "kind":
+		target = header.Kind
+	case "group":
+		target = header.Group()
+	case "namespace":
+		target = header.Metadata.Namespace
+	case "name":
+		target = header.Metadata.Name
+	case "id":
+		return t.regex.MatchString(id)
+	}
+	ok, _ := path.Match(t.glob, target)
+	return ok
+}
+
+// MatchPair reports whether either object of the pair matches the selector.
+func (s *ObjectSelector) MatchPair(pair *ObjectPair) bool {
+	if x := pair.Left; x != nil && s.Match(x.Header, pair.LeftID()) {
+		return true
+	}
+	if x := pair.Right; x != nil && s.Match(x.Header, pair.ID) {
+		return true
+	}
+	return false
+}
+
+type ObjectSelectors []*ObjectSelector
+
+func ParseObjectSelectors(xs []string) (ObjectSelectors, error) {
+	selectors := make(ObjectSelectors, len(xs))
+	for i, x := range xs {
+		s, err := ParseObjectSelector(x)
+		if err != nil {
+			return nil, err
+		}
+		selectors[i] = s
+	}
+	return selectors, nil
+}
+
+// MatchPair reports whether any selector matches the pair.
+func (s ObjectSelectors) MatchPair(pair *ObjectPair) bool {
+	for _, x := range s {
+		if x.MatchPair(pair) {
+			return true
+		}
+	}
+	return false
+}
+
+var _ ObjectPairer = &FilterPairer{}
+
+// FilterPairer keeps the pairs matching any of the includes, or all the pairs if no includes,
+// and drops the pairs matching any of the excludes.
+type FilterPairer struct {
+	pairer   ObjectPairer
+	includes ObjectSelectors
+	excludes ObjectSelectors
+}
+
+func NewFilterPairer(pairer ObjectPairer, includes, excludes ObjectSelectors) *FilterPairer {
+	return &FilterPairer{
+		pairer:   pairer,
+		includes: includes,
+		excludes: excludes,
+	}
+}
+
+func (p *FilterPairer) ObjectPairs() []*ObjectPair {
+	var xs []*ObjectPair
+	for _, x := range p.pairer.ObjectPairs() {
+		if len(p.includes) > 0 && !p.includes.MatchPair(x) {
+			continue
+		}
+		if p.excludes.MatchPair(x) {
+			continue
+		}
+		xs = append(xs, x)
+	}
+	return xs
+}
@@ -0,0 +1,165 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/berquerant/k8s-object-diff-go/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObjectSelector(t *testing.T) {
+	var (
+		deploy = internal.ObjectHeader{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Metadata: internal.ObjectMeta{
+				Namespace: "production",
+				Name:      "app-web",
+			},
+		}
+		cm = internal.ObjectHeader{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Metadata: internal.ObjectMeta{
+				Namespace: "production",
+				Name:      "app-config",
+			},
+		}
+		crd = internal.ObjectHeader{
+			APIVersion: "apiextensions.k8s.io/v1",
+			Kind:       "CustomResourceDefinition",
+			Metadata: internal.ObjectMeta{
+				Name: "apps.example.com",
+			},
+		}
+	)
+
+	for _, tc := range []struct {
+		title    string
+		selector string
+		want     []bool // deploy, cm, crd
+	}{
+		{
+			title:    "kind",
+			selector: "kind=Deployment",
+			want:     []bool{true, false, false},
+		},
+		{
+			title:    "kind and namespace",
+			selector: "kind=Deployment,namespace=staging",
+			want:     []bool{false, false, false},
+		},
+		{
+			title:    "group",
+			selector: "group=apiextensions.k8s.io",
+			want:     []bool{false, false, true},
+		},
+		{
+			title:    "core group",
+			selector: "group=",
+			want:     []bool{false, true, false},
+		},
+		{
+			title:    "name glob",
+			selector: "name=app-*",
+			want:     []bool{true, true, false},
+		},
+		{
+			title:    "id regex with comma",
+			selector: "namespace=production,id=^(apps/)?v1>[A-Z][a-z]{3,}",
+			want:     []bool{true, true, false},
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			s, err := internal.ParseObjectSelector(tc.selector)
+			if !assert.Nil(t, err) {
+				return
+			}
+			for i, h := range []internal.ObjectHeader{deploy, cm, crd} {
+				assert.Equal(t, tc.want[i], s.Match(h, h.IntoID(">")), h.IntoID(">"))
+			}
+		})
+	}
+
+	for _, s := range []string{
+		"",
+		"kind",
+		"label=app",
+		"name=[",
+		"id=(",
+	} {
+		t.Run("invalid "+s, func(t *testing.T) {
+			_, err := internal.ParseObjectSelector(s)
+			assert.ErrorIs(t, err, internal.ErrInvalidObjectSelector)
+		})
+	}
+}
+
+func TestFilterPairer(t *testing.T) {
+	newObject := func(kind, name string) *internal.Object {
+		return &internal.Object{
+			Header: internal.ObjectHeader{
+				APIVersion: "v1",
+				Kind:       kind,
+				Metadata: internal.ObjectMeta{
+					Name: name,
+				},
+			},
+		}
+	}
+	left := internal.NewObjectMap(">")
+	right := internal.NewObjectMap(">")
+	left.Add(newObject("ConfigMap", "a"))
+	right.Add(newObject("ConfigMap", "a"))
+	left.Add(newObject("Pod", "a"))
+	right.Add(newObject("Pod", "b"))
+	right.Add(newObject("Secret", "a"))
+
+	mustParse := func(xs ...string) internal.ObjectSelectors {
+		s, err := internal.ParseObjectSelectors(xs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	ids := func(pairs []*internal.ObjectPair) []string {
+		var xs []string
+		for _, x := range pairs {
+			xs = append(xs, x.ID)
+		}
+		return xs
+	}
+
+	for _, tc := range []struct {
+		title    string
+		includes internal.ObjectSelectors
+		excludes internal.ObjectSelectors
+		want     []string
+	}{
+		{
+			title: "all",
+			want:  []string{"v1>ConfigMap>>a", "v1>Pod>>a", "v1>Pod>>b", "v1>Secret>>a"},
+		},
+		{
+			title:    "include",
+			includes: mustParse("kind=Pod", "kind=Secret"),
+			want:     []string{"v1>Pod>>a", "v1>Pod>>b", "v1>Secret>>a"},
+		},
+		{
+			title:    "exclude",
+			excludes: mustParse("name=a"),
+			want:     []string{"v1>Pod>>b"},
+		},
+		{
+			title:    "include and exclude",
+			includes: mustParse("kind=Pod"),
+			excludes: mustParse("id=>b$"),
+			want:     []string{"v1>Pod>>a"},
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			p := internal.NewFilterPairer(internal.NewObjectPairMap(left, right), tc.includes, tc.excludes)
+			assert.Equal(t, tc.want, ids(p.ObjectPairs()))
+		})
+	}
+}
@@ -0,0 +1,294 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+type StructuralChangeType int
+
+const (
+	StructuralAdd StructuralChangeType = iota
+	StructuralRemove
+	StructuralReplace
+)
+
+func (t StructuralChangeType) String() string {
+	switch t {
+	case StructuralAdd:
+		return "+"
+	case StructuralRemove:
+		return "-"
+	default:
+		return "~"
+	}
+}
+
+func (t StructuralChangeType) Color() func(string) string {
+	switch t {
+	case StructuralAdd:
+		return greenString
+	case StructuralRemove:
+		return redString
+	default:
+		return yellowString
+	}
+}
+
+// StructuralChange is a change of the value at the path.
+type StructuralChange struct {
+	Type  StructuralChangeType
+	Path  Path
+	Left  any
+	Right any
+}
+
+func (c *StructuralChange) IntoString(color bool) string {
+	var x string
+	switch c.Type {
+	case StructuralAdd:
+		x = fmt.Sprintf("%s %s: %s", c.Type, c.Path, StructuralValueString(c.Right))
+	case StructuralRemove:
+		x = fmt.Sprintf("%s %s: %s", c.Type, c.Path, StructuralValueString(c.Left))
+	default:
+		x = fmt.Sprintf("%s %s: %s -> %s", c.Type, c.Path, StructuralValueString(c.Left), StructuralValueString(c.Right))
+	}
+	if color {
+		return c.Type.Color()(x)
+	}
+	return x
+}
+
+// StructuralValueString renders the value in a single line.
+func StructuralValueString(v any) string {
+	if v == nil {
+		return "null"
+	}
+	b, err := yaml.MarshalWithOptions(v, yaml.Flow(true))
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSuffix(string(b), "\n")
+}
+
+// StructuralDiff compares the decoded trees and reports the changes keyed by the paths.
+// Map entries are compared by key, so reordering maps yields no changes.
+// List elements are compared by the merge key from the rules,
+// or by name if they look like a named list, otherwise by index.
+func StructuralDiff(left, right any, rules MergeKeyRules) []*StructuralChange {
+	d := &structuralDiffer{
+		rules: rules,
+	}
+	for _, x := range []any{left, right} {
+		if v, ok := treeGet(x, "kind"); ok {
+			d.kind, _ = v.(string)
+			break
+		}
+	}
+	d.diff(nil, left, right)
+	return d.changes
+}
+
+type structuralDiffer struct {
+	rules   MergeKeyRules
+	kind    string
+	changes []*StructuralChange
+}
+
+func (d *structuralDiffer) add(t StructuralChangeType, path Path, left, right any) {
+	d.changes = append(d.changes, &StructuralChange{
+		Type:  t,
+		Path:  path,
+		Left:  left,
+		Right: right,
+	})
+}
+
+func (d *structuralDiffer) diff(path Path, left, right any) {
+	leftItems, leftIsMap := treeItems(left)
+	rightItems, rightIsMap := treeItems(right)
+	if len(path) == 0 {
+		// compare absent objects as empty ones
+		if left == nil && rightIsMap {
+			leftIsMap = true
+		}
+		if right == nil && leftIsMap {
+			rightIsMap = true
+		}
+	}
+	if leftIsMap && rightIsMap {
+		d.diffMap(path, leftItems, rightItems)
+		return
+	}
+
+	leftList, leftIsList := left.([]any)
+	rightList, rightIsList := right.([]any)
+	if leftIsList && rightIsList {
+		d.diffList(path, leftList, rightList)
+		return
+	}
+
+	if !reflect.DeepEqual(left, right) {
+		d.add(StructuralReplace, path, left, right)
+	}
+}
+
+func (d *structuralDiffer) diffMap(path Path, left, right yaml.MapSlice) {
+	for _, x := range left {
+		k := treeKeyString(x.Key)
+		p := path.Append(&PathElement{Type: PathKey, Key: k})
+		if y, ok := treeGet(right, k); ok {
+			d.diff(p, x.Value, y)
+			continue
+		}
+		d.add(StructuralRemove, p, x.Value, nil)
+	}
+	for _, x := range right {
+		k := treeKeyString(x.Key)
+		if _, ok := treeGet(left, k); !ok {
+			d.add(StructuralAdd, path.Append(&PathElement{Type: PathKey, Key: k}), nil, x.Value)
+		}
+	}
+}
+
+func (d *structuralDiffer) diffList(path Path, left, right []any) {
+	if key, ok := d.rules.mergeKeyOf(d.kind, path, left, right); ok {
+		d.diffKeyedList(path, key, left, right)
+		return
+	}
+	if key, ok := structuralListKey(left, right); ok {
+		d.diffKeyedList(path, key, left, right)
+		return
+	}
+
+	n := min(len(left), len(right))
+	for i := range n {
+		d.diff(path.Append(&PathElement{Type: PathIndex, Index: i}), left[i], right[i])
+	}
+	for i := n; i < len(left); i++ {
+		d.add(StructuralRemove, path.Append(&PathElement{Type: PathIndex, Index: i}), left[i], nil)
+	}
+	for i := n; i < len(right); i++ {
+		d.add(StructuralAdd, path.Append(&PathElement{Type: PathIndex, Index: i}), nil, right[i])
+	}
+}
+
+func (d *structuralDiffer) diffKeyedList(path Path, key string, left, right []any) {
+	var (
+		elementKey = func(x any) string {
+			v, _ := treeGet(x, key)
+			return fmt.Sprint(v)
+		}
+		newPath = func(x any) Path {
+			return path.Append(&PathElement{Type: PathMatch, Key: key, Value: elementKey(x)})
+		}
+		rightIndex = map[string]any{}
+		leftIndex  = map[string]bool{}
+	)
+	for _, x := range right {
+		rightIndex[elementKey(x)] = x
+	}
+	for _, x := range left {
+		k := elementKey(x)
+		leftIndex[k] = true
+		if y, ok := rightIndex[k]; ok {
+			d.diff(newPath(x), x, y)
+			continue
+		}
+		d.add(StructuralRemove, newPath(x), x, nil)
+	}
+	for _, x := range right {
+		if !leftIndex[elementKey(x)] {
+			d.add(StructuralAdd, newPath(x), nil, x)
+		}
+	}
+}
+
+// structuralListKey returns the field to identify list elements.
+// The lists are keyed by name when all the elements have distinct names.
+func structuralListKey(left, right []any) (string, bool) {
+	const key = "name"
+	isKeyed := func(xs []any) bool {
+		seen := map[string]bool{}
+		for _, x := range xs {
+			v, ok := treeGet(x, key)
+			if !ok {
+				return false
+			}
+			s, ok := v.(string)
+			if !ok || seen[s] {
+				return false
+			}
+			seen[s] = true
+		}
+		return true
+	}
+	if len(left) == 0 && len(right) == 0 {
+		return "", false
+	}
+	if isKeyed(left) && isKeyed(right) {
+		return key, true
+	}
+	return "", false
+}
+
+var _ ObjectDiffer = &StructuralObjectDiffer{}
+
+// StructuralObjectDiffer reports the changes of the objects keyed by the paths.
+//
+//	--- LEFT ID
+//	+++ RIGHT ID
+//	~ spec.replicas: 1 -> 3
+//	+ metadata.labels.tier: web
+//	- spec.template.spec.containers[name=sidecar]: {image: sidecar:1, name: sidecar}
+type StructuralObjectDiffer struct {
+	labels *DiffLabels
+	color  bool
+	rules  MergeKeyRules
+}
+
+func NewStructuralObjectDiffer(labels *DiffLabels, color bool, rules MergeKeyRules) *StructuralObjectDiffer {
+	return &StructuralObjectDiffer{
+		labels: labels,
+		color:  color,
+		rules:  rules,
+	}
+}
+
+func (d *StructuralObjectDiffer) header(pair *ObjectPair) string {
+	leftLabel, rightLabel := d.labels.Of(pair)
+	left := "--- " + newDiffHeader(leftLabel, pair.LeftID(), d.color)
+	right := "+++ " + newDiffHeader(rightLabel, pair.ID, d.color)
+	if d.color {
+		left = redString(left)
+		right = greenString(right)
+	}
+	return left + "\n" + right + "\n"
+}
+
+func (d *StructuralObjectDiffer) ObjectDiff(_ context.Context, pair *ObjectPair) (*ObjectDiff, error) {
+	var left, right any
+	if x := pair.Left; x != nil {
+		left = x.Value
+	}
+	if x := pair.Right; x != nil {
+		right = x.Value
+	}
+
+	changes := StructuralDiff(left, right, d.rules)
+	if len(changes) == 0 {
+		return newObjectDiff(pair, ""), nil
+	}
+
+	var b strings.Builder
+	b.WriteString(d.header(pair))
+	for _, c := range changes {
+		b.WriteString(c.IntoString(d.color))
+		b.WriteString("\n")
+	}
+	return newObjectDiff(pair, b.String()), nil
+}
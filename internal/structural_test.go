@@ -0,0 +1,219 @@
+package internal_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/berquerant/k8s-object-diff-go/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStructuralDiff(t *testing.T) {
+	decode := func(t *testing.T, s string) any {
+		t.Helper()
+		if s == "" {
+			return nil
+		}
+		xs, err := internal.NewYamlUnmarshaler(strings.NewReader(s), map[string]any{}, true).Unmarshal(context.TODO())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return xs[0]
+	}
+
+	for _, tc := range []struct {
+		title string
+		left  string
+		right string
+		want  []string
+	}{
+		{
+			title: "empty",
+		},
+		{
+			title: "reordered map",
+			left: `a: 1
+b:
+  c: 2
+  d: 3
+`,
+			right: `b:
+  d: 3
+  c: 2
+a: 1
+`,
+		},
+		{
+			title: "scalars",
+			left: `a: 1
+b: x
+c: "80"
+`,
+			right: `a: 2
+b: x
+c: 80
+`,
+			want: []string{
+				"~ a: 1 -> 2",
+				`~ c: "80" -> 80`,
+			},
+		},
+		{
+			title: "add and remove",
+			left: `a:
+  b: 1
+  c: 2
+`,
+			right: `a:
+  b: 1
+  d: [1, 2]
+`,
+			want: []string{
+				"- a.c: 2",
+				"+ a.d: [1, 2]",
+			},
+		},
+		{
+			title: "indexed list",
+			left: `a: [1, 2, 3]
+b: [1]
+`,
+			right: `a: [1, 4]
+b: [1, 2]
+`,
+			want: []string{
+				"~ a[1]: 2 -> 4",
+				"- a[2]: 3",
+				"+ b[1]: 2",
+			},
+		},
+		{
+			title: "named list",
+			left: `containers:
+- name: app
+  image: app:1
+- name: sidecar
+  image: sidecar:1
+`,
+			right: `containers:
+- name: init
+  image: init:1
+- name: app
+  image: app:2
+`,
+			want: []string{
+				`~ containers[name=app].image: "app:1" -> "app:2"`,
+				`- containers[name=sidecar]: {name: sidecar, image: "sidecar:1"}`,
+				`+ containers[name=init]: {name: init, image: "init:1"}`,
+			},
+		},
+		{
+			title: "merge key",
+			left: `kind: Pod
+spec:
+  containers:
+  - name: app
+    ports:
+    - containerPort: 80
+      name: http
+    - containerPort: 443
+      name: https
+`,
+			right: `kind: Pod
+spec:
+  containers:
+  - name: app
+    ports:
+    - containerPort: 8080
+      name: http
+    - containerPort: 443
+      name: tls
+`,
+			want: []string{
+				"- spec.containers[name=app].ports[containerPort=80]: {containerPort: 80, name: http}",
+				"~ spec.containers[name=app].ports[containerPort=443].name: https -> tls",
+				"+ spec.containers[name=app].ports[containerPort=8080]: {containerPort: 8080, name: http}",
+			},
+		},
+		{
+			title: "duplicated merge key",
+			left: `kind: Pod
+spec:
+  containers:
+  - name: app
+    ports:
+    - containerPort: 80
+      protocol: TCP
+    - containerPort: 80
+      protocol: UDP
+`,
+			right: `kind: Pod
+spec:
+  containers:
+  - name: app
+    ports:
+    - containerPort: 80
+      protocol: UDP
+    - containerPort: 80
+      protocol: TCP
+`,
+			want: []string{
+				"~ spec.containers[name=app].ports[0].protocol: TCP -> UDP",
+				"~ spec.containers[name=app].ports[1].protocol: UDP -> TCP",
+			},
+		},
+		{
+			title: "type changed",
+			left: `a:
+  b: 1
+`,
+			right: `a: [1]
+`,
+			want: []string{
+				"~ a: {b: 1} -> [1]",
+			},
+		},
+		{
+			title: "quoted key",
+			left: `metadata:
+  labels:
+    helm.sh/chart: a
+`,
+			right: `metadata:
+  labels:
+    helm.sh/chart: b
+`,
+			want: []string{
+				`~ metadata.labels["helm.sh/chart"]: a -> b`,
+			},
+		},
+		{
+			title: "left only",
+			left: `a: 1
+b: [1]
+`,
+			want: []string{
+				"- a: 1",
+				"- b: [1]",
+			},
+		},
+		{
+			title: "right only",
+			right: `a: 1
+`,
+			want: []string{
+				"+ a: 1",
+			},
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			changes := internal.StructuralDiff(decode(t, tc.left), decode(t, tc.right), internal.DefaultMergeKeyRules)
+			var got []string
+			for _, c := range changes {
+				got = append(got, c.IntoString(false))
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
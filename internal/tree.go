@@ -0,0 +1,235 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Decoded objects are trees of map[string]any (the document root),
+// yaml.MapSlice (nested maps), []any and scalars.
+
+func treeKeyString(k any) string {
+	if s, ok := k.(string); ok {
+		return s
+	}
+	return fmt.Sprint(k)
+}
+
+// treeItems returns the entries of a map node.
+func treeItems(v any) (yaml.MapSlice, bool) {
+	switch v := v.(type) {
+	case yaml.MapSlice:
+		return v, true
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		xs := make(yaml.MapSlice, len(keys))
+		for i, k := range keys {
+			xs[i] = yaml.MapItem{Key: k, Value: v[k]}
+		}
+		return xs, true
+	default:
+		return nil, false
+	}
+}
+
+// treeGet returns the value of the key of a map node.
+func treeGet(v any, key string) (any, bool) {
+	switch v := v.(type) {
+	case yaml.MapSlice:
+		for _, x := range v {
+			if treeKeyString(x.Key) == key {
+				return x.Value, true
+			}
+		}
+	case map[string]any:
+		x, ok := v[key]
+		return x, ok
+	}
+	return nil, false
+}
+
+// treeSet sets the value of the key of a map node and returns the updated node.
+func treeSet(v any, key string, value any) any {
+	switch v := v.(type) {
+	case yaml.MapSlice:
+		for i, x := range v {
+			if treeKeyString(x.Key) == key {
+				v[i].Value = value
+				return v
+			}
+		}
+		return append(v, yaml.MapItem{Key: key, Value: value})
+	case map[string]any:
+		v[key] = value
+		return v
+	default:
+		return v
+	}
+}
+
+// treeDelete deletes the key of a map node and returns the updated node.
+func treeDelete(v any, key string) any {
+	switch v := v.(type) {
+	case yaml.MapSlice:
+		xs := make(yaml.MapSlice, 0, len(v))
+		for _, x := range v {
+			if treeKeyString(x.Key) != key {
+				xs = append(xs, x)
+			}
+		}
+		return xs
+	case map[string]any:
+		delete(v, key)
+		return v
+	default:
+		return v
+	}
+}
+
+// treeMatch reports whether v is a map node that has the field with the value.
+func treeMatch(v any, key, value string) bool {
+	x, ok := treeGet(v, key)
+	if !ok {
+		return false
+	}
+	return fmt.Sprint(x) == value
+}
+
+// treeLen returns the number of the children of a map or list node, otherwise -1.
+func treeLen(v any) int {
+	switch v := v.(type) {
+	case yaml.MapSlice:
+		return len(v)
+	case map[string]any:
+		return len(v)
+	case []any:
+		return len(v)
+	default:
+		return -1
+	}
+}
+
+// treeRemoveChild removes the values pointed by the path from the child.
+// The child itself is dropped when it becomes empty by the removal
+// so that an emptied map and a missing map compare equal.
+func treeRemoveChild(child any, path Path) (any, bool) {
+	n := treeLen(child)
+	x := treeRemove(child, path)
+	return x, n == 0 || treeLen(x) != 0
+}
+
+// treeRemove removes the values pointed by the path and returns the updated node.
+func treeRemove(v any, path Path) any {
+	if len(path) == 0 {
+		return v
+	}
+	var (
+		head   = path[0]
+		isLast = len(path) == 1
+		rest   = path[1:]
+	)
+
+	if xs, ok := v.([]any); ok {
+		var match func(int, any) bool
+		switch head.Type {
+		case PathWildcard:
+			match = func(int, any) bool { return true }
+		case PathIndex:
+			match = func(i int, _ any) bool { return i == head.Index }
+		case PathMatch:
+			match = func(_ int, x any) bool { return treeMatch(x, head.Key, head.Value) }
+		default:
+			return v
+		}
+		result := make([]any, 0, len(xs))
+		for i, x := range xs {
+			switch {
+			case !match(i, x):
+				result = append(result, x)
+			case !isLast:
+				if y, ok := treeRemoveChild(x, rest); ok {
+					result = append(result, y)
+				}
+			}
+		}
+		return result
+	}
+
+	items, ok := treeItems(v)
+	if !ok {
+		return v
+	}
+	for _, x := range items {
+		k := treeKeyString(x.Key)
+		switch head.Type {
+		case PathWildcard:
+		case PathKey:
+			if k != head.Key {
+				continue
+			}
+		default:
+			continue
+		}
+		if isLast {
+			v = treeDelete(v, k)
+			continue
+		}
+		if y, ok := treeRemoveChild(x.Value, rest); ok {
+			v = treeSet(v, k, y)
+		} else {
+			v = treeDelete(v, k)
+		}
+	}
+	return v
+}
+
+// treeUpdate replaces the values pointed by the path with f of them and returns the updated node.
+func treeUpdate(v any, path Path, f func(any) any) any {
+	if len(path) == 0 {
+		return f(v)
+	}
+	head, rest := path[0], path[1:]
+
+	if xs, ok := v.([]any); ok {
+		for i, x := range xs {
+			var match bool
+			switch head.Type {
+			case PathWildcard:
+				match = true
+			case PathIndex:
+				match = i == head.Index
+			case PathMatch:
+				match = treeMatch(x, head.Key, head.Value)
+			}
+			if match {
+				xs[i] = treeUpdate(x, rest, f)
+			}
+		}
+		return xs
+	}
+
+	items, ok := treeItems(v)
+	if !ok {
+		return v
+	}
+	for _, x := range items {
+		k := treeKeyString(x.Key)
+		switch head.Type {
+		case PathWildcard:
+		case PathKey:
+			if k != head.Key {
+				continue
+			}
+		default:
+			continue
+		}
+		v = treeSet(v, k, treeUpdate(x.Value, rest, f))
+	}
+	return v
+}
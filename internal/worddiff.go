@@ -0,0 +1,183 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// WordDiffMode is the unit of the intra-line highlighting.
+type WordDiffMode int
+
+const (
+	WordDiffNone WordDiffMode = iota
+	WordDiffWord
+	WordDiffChar
+)
+
+func (m WordDiffMode) String() string {
+	switch m {
+	case WordDiffWord:
+		return "word"
+	case WordDiffChar:
+		return "char"
+	default:
+		return "none"
+	}
+}
+
+var ErrInvalidWordDiffMode = errors.New("InvalidWordDiffMode")
+
+func ParseWordDiffMode(s string) (WordDiffMode, error) {
+	switch s {
+	case "", "none":
+		return WordDiffNone, nil
+	case "word":
+		return WordDiffWord, nil
+	case "char":
+		return WordDiffChar, nil
+	default:
+		return WordDiffNone, fmt.Errorf("%s: %w", s, ErrInvalidWordDiffMode)
+	}
+}
+
+// intraLineDiffs diffs the paired lines by the unit of the mode.
+func intraLineDiffs(left, right string, mode WordDiffMode) []diffmatchpatch.Diff {
+	dmp := diffmatchpatch.New()
+	if mode != WordDiffWord {
+		return dmp.DiffCleanupSemantic(dmp.DiffMain(left, right, false))
+	}
+
+	// encode the words into runes to diff them like DiffLinesToChars
+	var (
+		words  []string
+		index  = map[string]rune{}
+		encode = func(s string) []rune {
+			var xs []rune
+			for _, w := range splitWords(s) {
+				r, ok := index[w]
+				if !ok {
+					r = rune(len(words))
+					if r >= 0xd800 { // skip surrogates
+						r += 0x800
+					}
+					index[w] = r
+					words = append(words, w)
+				}
+				xs = append(xs, r)
+			}
+			return xs
+		}
+		decode = func(s string) string {
+			var b strings.Builder
+			for _, r := range s {
+				if r >= 0xe000 {
+					r -= 0x800
+				}
+				b.WriteString(words[r])
+			}
+			return b.String()
+		}
+	)
+	diffs := dmp.DiffMainRunes(encode(left), encode(right), false)
+	for i, d := range diffs {
+		diffs[i].Text = decode(d.Text)
+	}
+	return diffs
+}
+
+// splitWords splits s into the runs of letters and digits, the runs of spaces and the other characters.
+func splitWords(s string) []string {
+	var (
+		words []string
+		start int
+		class = func(r rune) int {
+			switch {
+			case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+				return 1
+			case unicode.IsSpace(r):
+				return 2
+			default:
+				return 0
+			}
+		}
+		prev = -1
+	)
+	for i, r := range s {
+		c := class(r)
+		if i > 0 && (c != prev || c == 0) {
+			words = append(words, s[start:i])
+			start = i
+		}
+		prev = c
+	}
+	if start < len(s) {
+		words = append(words, s[start:])
+	}
+	return words
+}
+
+// wordDiffString renders the paired deleted and inserted lines.
+// With color, the changed spans of the lines are colored.
+// Without color, the paired lines are merged into a line with [-old-]{+new+} markers.
+func wordDiffString(deleted, inserted []string, color bool, mode WordDiffMode) string {
+	var (
+		b      strings.Builder
+		paired = min(len(deleted), len(inserted))
+		diffs  = make([][]diffmatchpatch.Diff, paired)
+	)
+	for i := range paired {
+		diffs[i] = intraLineDiffs(deleted[i], inserted[i], mode)
+	}
+
+	if !color {
+		for i := range paired {
+			b.WriteString(" ")
+			for _, d := range diffs[i] {
+				switch d.Type {
+				case diffmatchpatch.DiffDelete:
+					b.WriteString("[-" + d.Text + "-]")
+				case diffmatchpatch.DiffInsert:
+					b.WriteString("{+" + d.Text + "+}")
+				default:
+					b.WriteString(d.Text)
+				}
+			}
+			b.WriteString("\n")
+		}
+		for _, s := range deleted[paired:] {
+			b.WriteString("-" + s + "\n")
+		}
+		for _, s := range inserted[paired:] {
+			b.WriteString("+" + s + "\n")
+		}
+		return b.String()
+	}
+
+	write := func(op DMPOp, lines []string, skip diffmatchpatch.Operation) {
+		f := op.Color()
+		for i, s := range lines {
+			b.WriteString(f(op.String()))
+			if i >= paired {
+				b.WriteString(f(s) + "\n")
+				continue
+			}
+			for _, d := range diffs[i] {
+				switch d.Type {
+				case skip:
+				case diffmatchpatch.DiffEqual:
+					b.WriteString(d.Text)
+				default:
+					b.WriteString(f(d.Text))
+				}
+			}
+			b.WriteString("\n")
+		}
+	}
+	write(DMPOpDelete, deleted, diffmatchpatch.DiffInsert)
+	write(DMPOpInsert, inserted, diffmatchpatch.DiffDelete)
+	return b.String()
+}
@@ -0,0 +1,100 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/berquerant/k8s-object-diff-go/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWordDiffMode(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		want  internal.WordDiffMode
+		err   error
+	}{
+		{input: "", want: internal.WordDiffNone},
+		{input: "none", want: internal.WordDiffNone},
+		{input: "word", want: internal.WordDiffWord},
+		{input: "char", want: internal.WordDiffChar},
+		{input: "line", err: internal.ErrInvalidWordDiffMode},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := internal.ParseWordDiffMode(tc.input)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestDMPPatchIntoWordDiffString(t *testing.T) {
+	patch := &internal.DMPPatch{
+		LeftStart:   1,
+		RightStart:  1,
+		LeftLength:  3,
+		RightLength: 3,
+		Hunks: []*internal.DMPHunk{
+			{Op: internal.DMPOpEqual, Body: "name: app\n"},
+			{Op: internal.DMPOpDelete, Body: "image: nginx:1.14.3\nreplicas: 1\n"},
+			{Op: internal.DMPOpInsert, Body: "image: nginx:1.15.0\n"},
+			{Op: internal.DMPOpInsert, Body: "port: 80\n"},
+		},
+	}
+
+	for _, tc := range []struct {
+		title string
+		mode  internal.WordDiffMode
+		color bool
+		want  string
+	}{
+		{
+			title: "none",
+			mode:  internal.WordDiffNone,
+			want: `@@ -1,3 +1,3 @@
+ name: app
+-image: nginx:1.14.3
+-replicas: 1
++image: nginx:1.15.0
++port: 80
+`,
+		},
+		{
+			title: "word",
+			mode:  internal.WordDiffWord,
+			want: `@@ -1,3 +1,3 @@
+ name: app
+ image: nginx:1.[-14-]{+15+}.[-3-]{+0+}
+-replicas: 1
++port: 80
+`,
+		},
+		{
+			title: "char",
+			mode:  internal.WordDiffChar,
+			want: `@@ -1,3 +1,3 @@
+ name: app
+ image: nginx:1.1[-4.3-]{+5.0+}
+-replicas: 1
++port: 80
+`,
+		},
+		{
+			title: "word color",
+			mode:  internal.WordDiffWord,
+			color: true,
+			want: "\x1b[36m@@ -1,3 +1,3 @@\n\x1b[0m name: app\n" +
+				"\x1b[31m-\x1b[0mimage: nginx:1.\x1b[31m14\x1b[0m.\x1b[31m3\x1b[0m\n" +
+				"\x1b[31m-\x1b[0m\x1b[31mreplicas: 1\x1b[0m\n" +
+				"\x1b[32m+\x1b[0mimage: nginx:1.\x1b[32m15\x1b[0m.\x1b[32m0\x1b[0m\n" +
+				"\x1b[32m+port: 80\x1b[0m\n",
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			assert.Equal(t, tc.want, patch.IntoWordDiffString(tc.color, tc.mode))
+		})
+	}
+}
@@ -64,6 +64,26 @@ func NewYamlUnmarshaler[T any](r io.Reader, t T, allowDuplicateMapKey bool) *Yam
 }
 
 func (y *YamlUnmarshaler[T]) Unmarshal(ctx context.Context) ([]T, error) {
+	docs, err := y.UnmarshalDocuments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]T, len(docs))
+	for i, d := range docs {
+		result[i] = d.Value
+	}
+	return result, nil
+}
+
+// YamlDocument is a decoded document.
+type YamlDocument[T any] struct {
+	Value T
+	// Line is the 1-based line number of the document body.
+	Line int
+}
+
+// UnmarshalDocuments reads all documents and unmarshal them with the positions.
+func (y *YamlUnmarshaler[T]) UnmarshalDocuments(ctx context.Context) ([]*YamlDocument[T], error) {
 	b, err := io.ReadAll(y.r)
 	if err != nil {
 		return nil, fmt.Errorf("unmarshal read: %w", err)
@@ -84,10 +104,10 @@ func (y *YamlUnmarshaler[T]) Unmarshal(ctx context.Context) ([]T, error) {
 	if y.allowDuplicateMapKey {
 		decoderOpts = append(decoderOpts, yaml.AllowDuplicateMapKey())
 	}
-	result := []T{}
+	result := []*YamlDocument[T]{}
 	for i, d := range fileNode.Docs {
 		if d.Body == nil {
-			slog.Info("skip to load document due to empty", slog.Int("index", i))
+			slog.Debug("skip to load document due to empty", slog.Int("index", i))
 			continue
 		}
 		t := new(T)
@@ -97,7 +117,14 @@ func (y *YamlUnmarshaler[T]) Unmarshal(ctx context.Context) ([]T, error) {
 			slog.Info("failed to load document", slog.Int("index", i), slog.Any("err", err))
 			continue
 		}
-		result = append(result, *t)
+		var line int
+		if tk := d.Body.GetToken(); tk != nil && tk.Position != nil {
+			line = tk.Position.Line
+		}
+		result = append(result, &YamlDocument[T]{
+			Value: *t,
+			Line:  line,
+		})
 	}
 	return result, nil
 }